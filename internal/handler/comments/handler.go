@@ -0,0 +1,163 @@
+package comments
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/comment"
+	"in-server/pkg/apperr"
+)
+
+type Handler struct {
+	mu  sync.RWMutex
+	svc *comment.Service
+}
+
+func New(svc *comment.Service) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) SetService(svc *comment.Service) {
+	h.mu.Lock()
+	h.svc = svc
+	h.mu.Unlock()
+}
+
+func (h *Handler) getSvc() *comment.Service {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.svc
+}
+
+// Register mounts the reader routes under /posts/:id/comments.
+func (h *Handler) Register(r *gin.RouterGroup) {
+	r.GET("", h.thread)
+	r.POST("", h.create)
+	r.POST("/:commentId/verify", h.verify)
+	r.POST("/:commentId/moderate", h.moderate)
+}
+
+// RegisterModeration mounts the admin queue under /comments.
+func (h *Handler) RegisterModeration(r *gin.RouterGroup) {
+	r.POST("/moderation/list", h.queue)
+}
+
+func (h *Handler) thread(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+	items, err := svc.Thread(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+func (h *Handler) create(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+	var req struct {
+		ParentID string `json:"parentId"`
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		Body     string `json:"body"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+
+	item, err := svc.Create(c.Request.Context(), c.Param("id"), comment.CreateInput{
+		ParentID: req.ParentID,
+		Name:     req.Name,
+		Email:    req.Email,
+		Body:     req.Body,
+	}, httputil.ClientIP(c))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": item})
+}
+
+func (h *Handler) verify(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+	var req struct {
+		PinCode string `json:"pinCode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.PinCode) == "" {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+
+	item, err := svc.Verify(c.Request.Context(), c.Param("id"), c.Param("commentId"), req.PinCode)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+func (h *Handler) queue(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		Status    string `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+
+	items, err := svc.Queue(c.Request.Context(), req.AdminCode, comment.Status(strings.TrimSpace(req.Status)))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+func (h *Handler) moderate(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		Action    string `json:"action"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Comment.ErrInvalidBody)
+		return
+	}
+
+	action := comment.Action(strings.ToLower(strings.TrimSpace(req.Action)))
+	item, err := svc.Moderate(c.Request.Context(), req.AdminCode, c.Param("id"), c.Param("commentId"), action)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "action": action, "data": item})
+}
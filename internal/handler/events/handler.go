@@ -0,0 +1,92 @@
+package events
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/event"
+	"in-server/pkg/apperr"
+	"in-server/pkg/eth"
+	"in-server/pkg/types"
+)
+
+type Handler struct {
+	mu  sync.RWMutex
+	svc *event.Service
+}
+
+func New(svc *event.Service) *Handler { return &Handler{svc: svc} }
+
+func (h *Handler) SetService(svc *event.Service) {
+	h.mu.Lock()
+	h.svc = svc
+	h.mu.Unlock()
+}
+
+func (h *Handler) getSvc() *event.Service {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.svc
+}
+
+func (h *Handler) Register(r *gin.RouterGroup) {
+	r.POST("/list", h.list)
+}
+
+type listRequest struct {
+	AdminCode string `json:"adminCode"`
+	Contract  string `json:"contract"`
+	Name      string `json:"name"`
+	FromBlock uint64 `json:"fromBlock"`
+	ToBlock   uint64 `json:"toBlock"`
+	Limit     int    `json:"limit"`
+}
+
+// list returns indexed events, newest first. It is a POST so the admin code
+// travels in the body with the filters, like the other admin reads.
+func (h *Handler) list(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Event.ErrIndexerDisabled)
+		return
+	}
+	var req listRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Event.ErrInvalidQuery)
+		return
+	}
+	if err := svc.Authorize(req.AdminCode); err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	if req.Limit < 0 {
+		httputil.WriteError(c, apperr.Event.ErrInvalidQuery)
+		return
+	}
+
+	filter := eth.EventFilter{
+		Contract:  types.ContractName(strings.TrimSpace(req.Contract)),
+		Name:      strings.TrimSpace(req.Name),
+		FromBlock: req.FromBlock,
+		ToBlock:   req.ToBlock,
+		Limit:     req.Limit,
+	}
+
+	items, err := svc.Events(c.Request.Context(), filter)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	checkpoint, err := svc.Checkpoint(c.Request.Context())
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items, "checkpoint": checkpoint})
+}
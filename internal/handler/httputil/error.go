@@ -12,7 +12,11 @@ import (
 func WriteError(c *gin.Context, err error) {
 	var appErr *apperr.Error
 	if errors.As(err, &appErr) {
-		c.JSON(appErr.Status, gin.H{"code": appErr.Code, "message": appErr.Message})
+		body := gin.H{"code": appErr.Code, "message": appErr.Message}
+		if len(appErr.Fields) > 0 {
+			body["fields"] = appErr.Fields
+		}
+		c.JSON(appErr.Status, body)
 		return
 	}
 	c.JSON(http.StatusInternalServerError, gin.H{"code": "INTERNAL_SERVER_ERROR", "message": "internal server error"})
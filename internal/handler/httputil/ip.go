@@ -0,0 +1,14 @@
+package httputil
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP returns the caller's address. Forwarding headers are only read
+// when the request comes through one of TRUSTED_PROXIES, so clients cannot
+// choose their own IP for rate limits and dedupe.
+func ClientIP(c *gin.Context) string {
+	return strings.TrimSpace(c.ClientIP())
+}
@@ -0,0 +1,37 @@
+package post
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/pkg/apperr"
+)
+
+func (h *Handler) assist(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		Title     string `json:"title"`
+		Content   string `json:"content"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	suggestion, err := svc.Assist(c.Request.Context(), strings.TrimSpace(req.AdminCode), req.Title, req.Content)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": suggestion})
+}
@@ -0,0 +1,110 @@
+package post
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+// RegisterAuthors mounts the per-wallet author routes.
+func (h *Handler) RegisterAuthors(r *gin.RouterGroup) {
+	r.GET("/:address", h.getAuthor)
+	r.PUT("/:address", h.saveAuthor)
+	r.GET("/:address/posts", h.authorPosts)
+}
+
+func authorAddress(c *gin.Context) (common.Address, error) {
+	raw := strings.TrimSpace(c.Param("address"))
+	if !common.IsHexAddress(raw) {
+		return common.Address{}, apperr.Post.ErrInvalidOwner
+	}
+	return common.HexToAddress(raw), nil
+}
+
+func (h *Handler) getAuthor(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	addr, err := authorAddress(c)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+
+	profile, err := svc.Author(c.Request.Context(), addr)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": profile})
+}
+
+func (h *Handler) saveAuthor(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	addr, err := authorAddress(c)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	var req struct {
+		AdminCode   string `json:"adminCode"`
+		DisplayName string `json:"displayName"`
+		AvatarURL   string `json:"avatarUrl"`
+		Bio         string `json:"bio"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	profile, err := svc.SaveAuthor(c.Request.Context(), strings.TrimSpace(req.AdminCode), addr, post.AuthorProfile{
+		DisplayName: req.DisplayName,
+		AvatarURL:   req.AvatarURL,
+		Bio:         req.Bio,
+	})
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": profile})
+}
+
+func (h *Handler) authorPosts(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	addr, err := authorAddress(c)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	q, err := parseListQuery(c)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	q.Owner = addr
+
+	page, err := svc.Query(c.Request.Context(), q)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
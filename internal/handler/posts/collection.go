@@ -0,0 +1,120 @@
+package post
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+// RegisterCollections mounts series and curated collection routes.
+func (h *Handler) RegisterCollections(r *gin.RouterGroup) {
+	r.GET("", h.listCollections)
+	r.POST("", h.createCollection)
+	r.GET("/:collectionId", h.getCollection)
+	r.PUT("/:collectionId", h.updateCollection)
+	r.DELETE("/:collectionId", h.deleteCollection)
+}
+
+type collectionRequest struct {
+	AdminCode   string   `json:"adminCode"`
+	ID          string   `json:"id"`
+	Kind        string   `json:"kind"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	CoverURL    string   `json:"coverUrl"`
+	TokenIDs    []string `json:"tokenIds"`
+}
+
+func (r collectionRequest) collection() post.Collection {
+	return post.Collection{
+		ID:          r.ID,
+		Kind:        post.CollectionKind(strings.ToLower(strings.TrimSpace(r.Kind))),
+		Title:       r.Title,
+		Description: r.Description,
+		CoverURL:    r.CoverURL,
+		TokenIDs:    r.TokenIDs,
+	}
+}
+
+func (h *Handler) listCollections(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	items, err := svc.Collections(c.Request.Context())
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+func (h *Handler) getCollection(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	item, err := svc.Collection(c.Request.Context(), c.Param("collectionId"))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+func (h *Handler) createCollection(c *gin.Context) {
+	h.saveCollection(c, "", http.StatusCreated)
+}
+
+func (h *Handler) updateCollection(c *gin.Context) {
+	h.saveCollection(c, c.Param("collectionId"), http.StatusOK)
+}
+
+func (h *Handler) saveCollection(c *gin.Context, id string, status int) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req collectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	item, err := svc.SaveCollection(c.Request.Context(), strings.TrimSpace(req.AdminCode), id, req.collection())
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(status, gin.H{"data": item})
+}
+
+func (h *Handler) deleteCollection(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req adminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	if err := svc.DeleteCollection(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("collectionId")); err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
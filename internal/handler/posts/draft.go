@@ -0,0 +1,179 @@
+package post
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/pkg/apperr"
+)
+
+type draftRequest struct {
+	AdminCode   string          `json:"adminCode"`
+	MetadataURL *string         `json:"metadataUrl"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+type adminRequest struct {
+	AdminCode string `json:"adminCode"`
+}
+
+func (h *Handler) createDraft(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req draftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	payload, err := decodeNFTMetadata(req.Payload)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	metadataURL := ""
+	if req.MetadataURL != nil {
+		metadataURL = *req.MetadataURL
+	}
+
+	draft, err := svc.Create(c.Request.Context(), strings.TrimSpace(req.AdminCode), payload, metadataURL)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": draft})
+}
+
+func (h *Handler) listDrafts(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req adminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	drafts, err := svc.ListDrafts(c.Request.Context(), strings.TrimSpace(req.AdminCode))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": drafts})
+}
+
+func (h *Handler) getDraft(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req adminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	draft, err := svc.GetDraft(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("draftId"))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": draft})
+}
+
+func (h *Handler) updateDraft(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req draftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	payload, err := decodeNFTMetadata(req.Payload)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+
+	draft, err := svc.UpdateDraft(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("draftId"), payload, req.MetadataURL)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": draft})
+}
+
+func (h *Handler) deleteDraft(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req adminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	if err := svc.DeleteDraft(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("draftId")); err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) promoteDraft(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		Notify    bool   `json:"notifySubscribers"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	adminCode := strings.TrimSpace(req.AdminCode)
+	metadataURL, err := svc.PromoteDraft(c.Request.Context(), adminCode, c.Param("draftId"))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	resp := gin.H{"ok": true, "metadataUrl": metadataURL}
+	if req.Notify {
+		newsletter, err := svc.EnqueueNewsletter(c.Request.Context(), adminCode, metadataURL)
+		if err != nil {
+			// The post is already out; the newsletter can be queued again
+			// through POST /posts/:id/notify.
+			log.Println("enqueue newsletter:", err)
+		} else {
+			resp["newsletter"] = newsletter
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,138 @@
+package post
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+const feedCacheControl = "public, max-age=600"
+
+func (h *Handler) rss(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	feed, err := svc.Feed(c.Request.Context(), c.Query("lab"), requestURL(c, svc))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	body, err := feed.RSS()
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	writeCachedXML(c, "application/rss+xml; charset=utf-8", body, feed.Updated)
+}
+
+func (h *Handler) atom(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	feed, err := svc.Feed(c.Request.Context(), c.Query("lab"), requestURL(c, svc))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	body, err := feed.Atom()
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	writeCachedXML(c, "application/atom+xml; charset=utf-8", body, feed.Updated)
+}
+
+// writeCachedXML writes body with validators so feed readers and crawlers can
+// poll with conditional requests.
+func writeCachedXML(c *gin.Context, contentType string, body []byte, lastModified time.Time) {
+	sum := sha256.Sum256(body)
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+
+	c.Header("Cache-Control", feedCacheControl)
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func (h *Handler) sitemap(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	sitemap, err := svc.Sitemap(c.Request.Context())
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+
+	var body []byte
+	pageParam := strings.TrimSpace(c.Query("page"))
+	switch {
+	case pageParam != "":
+		page, convErr := strconv.Atoi(pageParam)
+		if convErr != nil || page < 1 || page > sitemap.Pages() {
+			httputil.WriteError(c, apperr.Post.ErrSitemapPageNotFound)
+			return
+		}
+		body, err = sitemap.Page(page)
+	case sitemap.Pages() > 1:
+		pagePath := publicBaseURL(c, svc) + c.Request.URL.Path
+		body, err = sitemap.Index(func(n int) string { return fmt.Sprintf("%s?page=%d", pagePath, n) })
+	default:
+		body, err = sitemap.Page(1)
+	}
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	writeCachedXML(c, "application/xml; charset=utf-8", body, sitemap.LastModified())
+}
+
+// Robots serves robots.txt pointing crawlers at the posts sitemap.
+func (h *Handler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", feedCacheControl)
+	c.String(http.StatusOK, post.Robots(publicBaseURL(c, h.getSvc())+"/posts/sitemap.xml"))
+}
+
+func requestURL(c *gin.Context, svc *post.Service) string {
+	return publicBaseURL(c, svc) + c.Request.URL.RequestURI()
+}
+
+// publicBaseURL is the origin written into publicly cached documents. It is
+// SITE_URL when configured; otherwise the request's own Host. Forwarding
+// headers are never read here, since one spoofed request would poison every
+// cache holding the response.
+func publicBaseURL(c *gin.Context, svc *post.Service) string {
+	if svc != nil {
+		if siteURL := svc.SiteURL(); siteURL != "" {
+			return siteURL
+		}
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, strings.TrimSpace(c.Request.Host))
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 
 	"in-server/internal/handler/httputil"
@@ -34,10 +36,43 @@ func (h *Handler) getSvc() *post.Service {
 	return h.svc
 }
 
+// Register mounts the post routes. Admin endpoints take adminCode in the
+// JSON body. Writes use REST verbs on the resource path; reads that need the
+// code cannot be GET requests, so they are POST on an explicit action path
+// such as /drafts/list or /drafts/:draftId/read.
 func (h *Handler) Register(r *gin.RouterGroup) {
 	r.GET("", h.list)
-	r.POST("", h.create)
+	r.POST("", h.createDraft)
+	r.POST("/drafts/list", h.listDrafts)
+	r.POST("/drafts/:draftId/read", h.getDraft)
+	r.PUT("/drafts/:draftId", h.updateDraft)
+	r.DELETE("/drafts/:draftId", h.deleteDraft)
+	r.POST("/drafts/:draftId/publish", h.promoteDraft)
+	r.POST("/drafts/:draftId/preview", h.previewDraft)
 	r.POST("/publish", h.publish)
+	r.POST("/migrate", h.migrate)
+	r.POST("/assist", h.assist)
+	r.POST("/newsletters/list", h.listNewsletters)
+	r.POST("/newsletters/:newsletterId/retry", h.retryNewsletter)
+	r.POST("/schedule", h.schedule)
+	r.POST("/schedule/list", h.listScheduled)
+	r.DELETE("/schedule/:jobId", h.cancelScheduled)
+	r.POST("/cache/stats", h.cacheStats)
+	r.GET("/feed.xml", h.rss)
+	r.GET("/atom.xml", h.atom)
+	r.GET("/sitemap.xml", h.sitemap)
+	r.GET("/popular", h.popular)
+	r.GET("/:id", h.get)
+	r.DELETE("/:id", h.delete)
+	r.POST("/:id/transfer", h.transfer)
+	r.POST("/:id/notify", h.notify)
+	r.GET("/:id/related", h.related)
+	r.GET("/:id/reactions", h.reactions)
+	r.POST("/:id/reactions", h.react)
+	r.GET("/:id/revisions", h.revisions)
+	r.GET("/:id/revisions/diff", h.diffRevisions)
+	r.POST("/:id/revisions/:rev/rollback", h.rollback)
+	r.POST("/:id/revisions/:rev/preview", h.previewRevision)
 }
 
 func (h *Handler) list(c *gin.Context) {
@@ -46,30 +81,94 @@ func (h *Handler) list(c *gin.Context) {
 		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
 		return
 	}
-	items, err := svc.List()
+	q, err := parseListQuery(c)
 	if err != nil {
 		httputil.WriteError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": items})
+	page, err := svc.Query(c.Request.Context(), q)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func parseListQuery(c *gin.Context) (post.ListQuery, error) {
+	q := post.ListQuery{
+		Lab:      c.Query("lab"),
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+		Q:        c.Query("q"),
+		Sort:     c.Query("sort"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	if owner := strings.TrimSpace(c.Query("owner")); owner != "" {
+		if !common.IsHexAddress(owner) {
+			return post.ListQuery{}, apperr.Post.ErrInvalidOwner
+		}
+		q.Owner = common.HexToAddress(owner)
+	}
+
+	var err error
+	if q.Before, err = post.ParseQueryTime(c.Query("before")); err != nil {
+		return post.ListQuery{}, err
+	}
+	if q.After, err = post.ParseQueryTime(c.Query("after")); err != nil {
+		return post.ListQuery{}, err
+	}
+
+	if limitParam := strings.TrimSpace(c.Query("limit")); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			return post.ListQuery{}, apperr.Post.ErrInvalidQuery
+		}
+		if limit > post.MaxListLimit {
+			limit = post.MaxListLimit
+		}
+		q.Limit = limit
+	}
+	return q, nil
 }
 
-func (h *Handler) create(c *gin.Context) {
+func (h *Handler) cacheStats(c *gin.Context) {
 	svc := h.getSvc()
 	if svc == nil {
 		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
 		return
 	}
-	var req post.Post
+	var req adminRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+	if err := svc.AuthorizeServerAdmin(req.AdminCode); err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": svc.CacheStats()})
+}
+
+func (h *Handler) get(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
 		return
 	}
-	if err := svc.Create(req); err != nil {
+	item, err := svc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
 		httputil.WriteError(c, err)
 		return
 	}
-	c.Status(http.StatusCreated)
+	if strings.EqualFold(strings.TrimSpace(c.Query("render")), "html") {
+		if item, err = svc.WithRenderedContent(c.Request.Context(), item); err != nil {
+			httputil.WriteError(c, err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": item})
 }
 
 func (h *Handler) publish(c *gin.Context) {
@@ -82,6 +181,7 @@ func (h *Handler) publish(c *gin.Context) {
 		AdminCode   string          `json:"adminCode"`
 		MetadataURL *string         `json:"metadataUrl"`
 		Payload     json.RawMessage `json:"payload"`
+		Notify      bool            `json:"notifySubscribers"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Println(err)
@@ -107,10 +207,61 @@ func (h *Handler) publish(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true, "metadataUrl": savedMetadataURL})
+	resp := gin.H{"ok": true, "metadataUrl": savedMetadataURL}
+	if req.Notify {
+		newsletter, err := svc.EnqueueNewsletter(c.Request.Context(), adminCode, savedMetadataURL)
+		if err != nil {
+			// The post is already out; the newsletter can be queued again
+			// through POST /posts/:id/notify.
+			log.Println("enqueue newsletter:", err)
+		} else {
+			resp["newsletter"] = newsletter
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *Handler) delete(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		Metadata  string `json:"metadata"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	action := post.MetadataAction(strings.ToLower(strings.TrimSpace(req.Metadata)))
+	result, err := svc.Delete(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("id"), action)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true, "data": result})
 }
 
 func parseNFTMetadata(raw json.RawMessage) (post.NftMetadata, error) {
+	meta, err := decodeNFTMetadata(raw)
+	if err != nil {
+		return post.NftMetadata{}, err
+	}
+
+	if err := post.ValidateMetadata(meta); err != nil {
+		return post.NftMetadata{}, err
+	}
+	return meta, nil
+}
+
+// decodeNFTMetadata accepts the payload either as a JSON object or as a JSON
+// string containing the object.
+func decodeNFTMetadata(raw json.RawMessage) (post.NftMetadata, error) {
 	raw = bytes.TrimSpace(raw)
 	if len(raw) == 0 {
 		return post.NftMetadata{}, apperr.Post.ErrInvalidBody
@@ -128,12 +279,5 @@ func parseNFTMetadata(raw json.RawMessage) (post.NftMetadata, error) {
 	if err := json.Unmarshal(raw, &meta); err != nil {
 		return post.NftMetadata{}, apperr.Post.ErrInvalidBody
 	}
-
-	if strings.TrimSpace(meta.Name) == "" ||
-		strings.TrimSpace(meta.Description) == "" ||
-		strings.TrimSpace(meta.ExternalURL) == "" ||
-		len(meta.Attributes) == 0 {
-		return post.NftMetadata{}, apperr.Post.ErrInvalidBody
-	}
 	return meta, nil
 }
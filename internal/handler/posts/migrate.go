@@ -0,0 +1,78 @@
+package post
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+func (h *Handler) migrate(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode string            `json:"adminCode"`
+		Items     []json.RawMessage `json:"items"`
+		ChunkSize int               `json:"chunkSize"`
+		DryRun    bool              `json:"dryRun"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	if len(req.Items) == 0 {
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+	// Migrate checks the code too, but it is skipped when no item decodes.
+	if err := svc.AuthorizeWallet(strings.TrimSpace(req.AdminCode)); err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+
+	// Items that do not decode are reported as failed instead of aborting
+	// the rest of the batch.
+	var (
+		items   []post.NftMetadata
+		indexes []int
+		failed  []post.MigrateItemResult
+	)
+	for i, raw := range req.Items {
+		item, err := decodeNFTMetadata(raw)
+		if err != nil {
+			failed = append(failed, post.MigrateItemResult{Index: i, Status: post.MigrateFailed, Error: apperr.Post.ErrInvalidBody.Message})
+			continue
+		}
+		items = append(items, item)
+		indexes = append(indexes, i)
+	}
+
+	report := post.MigrateReport{DryRun: req.DryRun}
+	if len(items) > 0 {
+		var err error
+		report, err = svc.Migrate(c.Request.Context(), strings.TrimSpace(req.AdminCode), items, req.ChunkSize, req.DryRun)
+		if err != nil {
+			httputil.WriteError(c, err)
+			return
+		}
+	}
+	for i := range report.Items {
+		report.Items[i].Index = indexes[i]
+	}
+	report.Items = append(report.Items, failed...)
+	report.Failed += len(failed)
+	sort.Slice(report.Items, func(i, j int) bool { return report.Items[i].Index < report.Items[j].Index })
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
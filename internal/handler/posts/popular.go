@@ -0,0 +1,43 @@
+package post
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/pkg/apperr"
+)
+
+// popular ranks posts by aggregated views. Query: days (default 7, max 90),
+// limit.
+func (h *Handler) popular(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+
+	params := map[string]int{"days": 0, "limit": 0}
+	for key := range params {
+		raw := strings.TrimSpace(c.Query(key))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			httputil.WriteError(c, apperr.Post.ErrInvalidQuery)
+			return
+		}
+		params[key] = n
+	}
+
+	items, err := svc.Popular(c.Request.Context(), params["days"], params["limit"])
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
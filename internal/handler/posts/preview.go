@@ -0,0 +1,82 @@
+package post
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+// RegisterPreview mounts the public preview route. Anyone holding a valid
+// token can read the draft or revision it was issued for.
+func (h *Handler) RegisterPreview(r *gin.RouterGroup) {
+	r.GET("/:token", h.preview)
+}
+
+type previewRequest struct {
+	AdminCode string `json:"adminCode"`
+	// TTLHours defaults to 72 and may not exceed 720.
+	TTLHours int `json:"ttlHours"`
+}
+
+func (h *Handler) previewDraft(c *gin.Context) {
+	h.createPreview(c, post.PreviewRequest{DraftID: c.Param("draftId")})
+}
+
+func (h *Handler) previewRevision(c *gin.Context) {
+	n, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || n < 1 {
+		httputil.WriteError(c, apperr.Post.ErrRevisionNotFound)
+		return
+	}
+	h.createPreview(c, post.PreviewRequest{Ref: c.Param("id"), Revision: n})
+}
+
+func (h *Handler) createPreview(c *gin.Context, in post.PreviewRequest) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req previewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+	if req.TTLHours < 0 {
+		httputil.WriteError(c, apperr.Post.ErrInvalidPreview)
+		return
+	}
+
+	in.TTL = time.Duration(req.TTLHours) * time.Hour
+	link, err := svc.CreatePreview(c.Request.Context(), strings.TrimSpace(req.AdminCode), in)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "data": link})
+}
+
+func (h *Handler) preview(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	p, err := svc.Preview(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.JSON(http.StatusOK, gin.H{"data": p})
+}
@@ -0,0 +1,37 @@
+package post
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/pkg/apperr"
+)
+
+func (h *Handler) related(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+
+	limit := 0
+	if limitParam := strings.TrimSpace(c.Query("limit")); limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n < 1 {
+			httputil.WriteError(c, apperr.Post.ErrInvalidQuery)
+			return
+		}
+		limit = n
+	}
+
+	items, err := svc.Related(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
@@ -0,0 +1,74 @@
+package post
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/pkg/apperr"
+)
+
+func (h *Handler) revisions(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	revs, err := svc.Revisions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": revs})
+}
+
+func (h *Handler) diffRevisions(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	from, errFrom := strconv.Atoi(strings.TrimSpace(c.Query("from")))
+	to, errTo := strconv.Atoi(strings.TrimSpace(c.Query("to")))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		httputil.WriteError(c, apperr.Post.ErrInvalidQuery)
+		return
+	}
+
+	diff, err := svc.DiffRevisions(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": diff})
+}
+
+func (h *Handler) rollback(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req adminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+	n, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || n < 1 {
+		httputil.WriteError(c, apperr.Post.ErrRevisionNotFound)
+		return
+	}
+
+	rev, err := svc.Rollback(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("id"), n)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "data": rev})
+}
@@ -0,0 +1,103 @@
+package post
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+func (h *Handler) schedule(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode   string          `json:"adminCode"`
+		MetadataURL *string         `json:"metadataUrl"`
+		PublishAt   string          `json:"publishAt"`
+		Payload     json.RawMessage `json:"payload"`
+		Notify      bool            `json:"notifySubscribers"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	publishAt, err := time.Parse(time.RFC3339, strings.TrimSpace(req.PublishAt))
+	if err != nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidPublishAt)
+		return
+	}
+
+	payload, err := parseNFTMetadata(req.Payload)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	metadataURL := ""
+	if req.MetadataURL != nil {
+		metadataURL = strings.TrimSpace(*req.MetadataURL)
+	}
+
+	job, err := svc.Schedule(c.Request.Context(), strings.TrimSpace(req.AdminCode), payload, metadataURL, publishAt, req.Notify)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": job})
+}
+
+func (h *Handler) listScheduled(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		Status    string `json:"status"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	status := post.JobStatus(strings.ToLower(strings.TrimSpace(req.Status)))
+	jobs, err := svc.ListScheduled(c.Request.Context(), strings.TrimSpace(req.AdminCode), status)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": jobs})
+}
+
+func (h *Handler) cancelScheduled(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req adminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	if err := svc.CancelScheduled(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("jobId")); err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
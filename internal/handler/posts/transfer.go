@@ -0,0 +1,46 @@
+package post
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+
+	"in-server/internal/handler/httputil"
+	"in-server/internal/service/post"
+	"in-server/pkg/apperr"
+)
+
+func (h *Handler) transfer(c *gin.Context) {
+	svc := h.getSvc()
+	if svc == nil {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRequest)
+		return
+	}
+	var req struct {
+		AdminCode string `json:"adminCode"`
+		To        string `json:"to"`
+		Metadata  string `json:"metadata"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		httputil.WriteError(c, apperr.Post.ErrInvalidBody)
+		return
+	}
+
+	to := strings.TrimSpace(req.To)
+	if !common.IsHexAddress(to) {
+		httputil.WriteError(c, apperr.Post.ErrInvalidRecipient)
+		return
+	}
+
+	mode := post.TransferMode(strings.ToLower(strings.TrimSpace(req.Metadata)))
+	result, err := svc.Transfer(c.Request.Context(), strings.TrimSpace(req.AdminCode), c.Param("id"), common.HexToAddress(to), mode)
+	if err != nil {
+		httputil.WriteError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	eventsvc "in-server/internal/service/event"
+	postsvc "in-server/internal/service/post"
+)
+
+// startJobs launches the in-process background loops. Each tick reads the
+// current services so reloads swap them in without restarting the loops.
+func (s *Server) startJobs(ctx context.Context) {
+	s.mu.RLock()
+	interval := s.cfg.Scheduler.Interval
+	indexInterval := s.cfg.Indexer.Interval
+	newsletterInterval := s.cfg.Newsletter.Interval
+	viewsInterval := s.cfg.Views.Interval
+	s.mu.RUnlock()
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if newsletterInterval <= 0 {
+		newsletterInterval = time.Minute
+	}
+	if indexInterval <= 0 {
+		indexInterval = 15 * time.Second
+	}
+	if viewsInterval <= 0 {
+		viewsInterval = time.Hour
+	}
+
+	go s.every(ctx, "scheduled posts", interval, func(ctx context.Context) error {
+		svc := s.currentPostSvc()
+		if svc == nil {
+			return nil
+		}
+		return svc.ProcessScheduled(ctx)
+	})
+
+	go s.every(ctx, "newsletters", newsletterInterval, func(ctx context.Context) error {
+		svc := s.currentPostSvc()
+		if svc == nil {
+			return nil
+		}
+		return svc.ProcessNewsletters(ctx)
+	})
+
+	go s.every(ctx, "post views", viewsInterval, func(ctx context.Context) error {
+		svc := s.currentPostSvc()
+		if svc == nil {
+			return nil
+		}
+		_, err := svc.AggregateViews(ctx)
+		return err
+	})
+
+	go s.every(ctx, "event indexer", indexInterval, func(ctx context.Context) error {
+		svc := s.currentEventSvc()
+		if !svc.Enabled() {
+			return nil
+		}
+		_, err := svc.Sync(ctx)
+		return err
+	})
+}
+
+func (s *Server) every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				s.log.Warn("background job failed", zap.String("job", name), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *Server) currentPostSvc() *postsvc.Service {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.postSvc
+}
+
+func (s *Server) currentEventSvc() *eventsvc.Service {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.eventSvc
+}
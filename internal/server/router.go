@@ -5,7 +5,9 @@ import (
 
 	"go.uber.org/zap"
 
+	commentshandler "in-server/internal/handler/comments"
 	emailhandler "in-server/internal/handler/email"
+	eventshandler "in-server/internal/handler/events"
 	googhandler "in-server/internal/handler/google"
 	"in-server/internal/handler/health"
 	mediahandler "in-server/internal/handler/media"
@@ -14,7 +16,9 @@ import (
 	visitorshandler "in-server/internal/handler/visitors"
 	"in-server/internal/server/router"
 
+	commentsvc "in-server/internal/service/comment"
 	emailsvc "in-server/internal/service/email"
+	eventsvc "in-server/internal/service/event"
 	googlesvc "in-server/internal/service/google"
 	mediasvc "in-server/internal/service/media"
 	postsvc "in-server/internal/service/post"
@@ -47,6 +51,15 @@ func (s *Server) registerRoutes() {
 	if err != nil {
 		s.log.Fatal("init media service", zap.Error(err))
 	}
+	eventSvc, err := eventsvc.New(context.Background(), s.cfg)
+	if err != nil {
+		s.log.Fatal("init event service", zap.Error(err))
+	}
+
+	commentSvc, err := commentsvc.New(context.Background(), s.cfg, emailSvc, postSvc)
+	if err != nil {
+		s.log.Fatal("init comment service", zap.Error(err))
+	}
 
 	healthHandler := health.New(s.cfg)
 	googleHandler := googhandler.New(googleSvc, s.reloadAll)
@@ -55,13 +68,19 @@ func (s *Server) registerRoutes() {
 	postHandler := posthandler.New(postSvc)
 	visitorsHandler := visitorshandler.New(visitorSvc)
 	subscriberHandler := subscriberhandler.New(subscriberSvc)
+	eventsHandler := eventshandler.New(eventSvc)
+	commentsHandler := commentshandler.New(commentSvc)
 
 	s.googleHandler = googleHandler
 	s.emailHandler = emailHandler
 	s.mediaHandler = mediaHandler
 	s.postHandler = postHandler
+	s.postSvc = postSvc
 	s.visitorsHandler = visitorsHandler
 	s.subscriberHandler = subscriberHandler
+	s.eventsHandler = eventsHandler
+	s.eventSvc = eventSvc
+	s.commentsHandler = commentsHandler
 
 	r := s.engine
 	{
@@ -72,5 +91,7 @@ func (s *Server) registerRoutes() {
 		router.RegisterVisitorRoutes(r, visitorsHandler)
 		router.RegisterSubscriberRoutes(r, subscriberHandler)
 		router.RegisterMediaRoutes(r, mediaHandler)
+		router.RegisterEventRoutes(r, eventsHandler)
+		router.RegisterCommentRoutes(r, commentsHandler)
 	}
 }
@@ -0,0 +1,12 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	commentshandler "in-server/internal/handler/comments"
+)
+
+func RegisterCommentRoutes(r gin.IRouter, h *commentshandler.Handler) {
+	h.Register(r.Group("/posts/:id/comments"))
+	h.RegisterModeration(r.Group("/comments"))
+}
@@ -0,0 +1,11 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	eventshandler "in-server/internal/handler/events"
+)
+
+func RegisterEventRoutes(r gin.IRouter, h *eventshandler.Handler) {
+	h.Register(r.Group("/events"))
+}
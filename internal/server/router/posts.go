@@ -7,5 +7,9 @@ import (
 )
 
 func RegisterPostRoutes(r gin.IRouter, h *posthandler.Handler) {
+	r.GET("/robots.txt", h.Robots)
 	h.Register(r.Group("/posts"))
+	h.RegisterAuthors(r.Group("/authors"))
+	h.RegisterCollections(r.Group("/collections"))
+	h.RegisterPreview(r.Group("/preview"))
 }
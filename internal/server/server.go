@@ -9,13 +9,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	commentshandler "in-server/internal/handler/comments"
 	emailhandler "in-server/internal/handler/email"
+	eventshandler "in-server/internal/handler/events"
 	googhandler "in-server/internal/handler/google"
 	mediahandler "in-server/internal/handler/media"
 	posthandler "in-server/internal/handler/posts"
 	subscriberhandler "in-server/internal/handler/subscriber"
 	visitorshandler "in-server/internal/handler/visitors"
+	commentsvc "in-server/internal/service/comment"
 	emailsvc "in-server/internal/service/email"
+	eventsvc "in-server/internal/service/event"
 	googlesvc "in-server/internal/service/google"
 	mediasvc "in-server/internal/service/media"
 	postsvc "in-server/internal/service/post"
@@ -35,6 +39,10 @@ type Server struct {
 	visitorsHandler   *visitorshandler.Handler
 	subscriberHandler *subscriberhandler.Handler
 	googleHandler     *googhandler.Handler
+	eventsHandler     *eventshandler.Handler
+	commentsHandler   *commentshandler.Handler
+	postSvc           *postsvc.Service
+	eventSvc          *eventsvc.Service
 	mu                sync.RWMutex
 }
 
@@ -42,6 +50,12 @@ func New(cfg config.Config, log *zap.Logger) *Server {
 	setGinMode(cfg.Env)
 
 	engine := gin.New()
+	if len(cfg.TrustedProxies) == 0 && cfg.Env == "production" {
+		log.Fatal("TRUSTED_PROXIES is empty; client IPs would all resolve to the proxy")
+	}
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES", zap.Error(err))
+	}
 	engine.Use(gin.Recovery())
 	engine.Use(gin.Logger())
 	engine.Use(cors.New(cors.Config{
@@ -96,9 +110,19 @@ func (s *Server) reloadAll(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	eventSvc, err := eventsvc.New(ctx, newCfg)
+	if err != nil {
+		return err
+	}
+	commentSvc, err := commentsvc.New(ctx, newCfg, emailSvc, postSvc)
+	if err != nil {
+		return err
+	}
 
 	s.mu.Lock()
 	s.cfg = newCfg
+	s.postSvc = postSvc
+	s.eventSvc = eventSvc
 	if s.emailHandler != nil {
 		s.emailHandler.SetService(emailSvc)
 	}
@@ -117,12 +141,19 @@ func (s *Server) reloadAll(ctx context.Context) error {
 	if s.googleHandler != nil {
 		s.googleHandler.SetService(googleSvc)
 	}
+	if s.eventsHandler != nil {
+		s.eventsHandler.SetService(eventSvc)
+	}
+	if s.commentsHandler != nil {
+		s.commentsHandler.SetService(commentSvc)
+	}
 	s.mu.Unlock()
 
 	return nil
 }
 
 func (s *Server) Run() error {
+	s.startJobs(context.Background())
 	s.log.Info("starting http server", zap.String("addr", s.cfg.Port))
 	return s.engine.Run(s.cfg.Port)
 }
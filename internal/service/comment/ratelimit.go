@@ -0,0 +1,55 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/firebase"
+)
+
+// checkRate records a comment attempt for ipHash and rejects it once
+// COMMENTS_RATE_LIMIT attempts fall inside COMMENTS_RATE_WINDOW. Attempts are
+// kept in Firebase so the limit holds across instances and restarts.
+func (s *Service) checkRate(ctx context.Context, ipHash string) error {
+	limit, window := s.cfg.Comments.RateLimit, s.cfg.Comments.RateWindow
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+
+	path := s.ratePath(ipHash)
+	attempts, _, err := firebase.Read[[]int64](ctx, s.fb, path)
+	if err != nil {
+		return apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+	}
+
+	now := time.Now()
+	recent, allowed := allowAttempt(attempts, now, limit, window)
+	if !allowed {
+		return apperr.Comment.ErrRateLimited
+	}
+	if err := firebase.Write(ctx, s.fb, path, recent); err != nil {
+		return apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+	}
+	return nil
+}
+
+// allowAttempt drops attempts older than window and, when fewer than limit
+// remain, appends now. The returned slice is what should be persisted.
+func allowAttempt(attempts []int64, now time.Time, limit int, window time.Duration) ([]int64, bool) {
+	cutoff := now.Add(-window).Unix()
+	recent := make([]int64, 0, len(attempts)+1)
+	for _, at := range attempts {
+		if at > cutoff {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= limit {
+		return recent, false
+	}
+	return append(recent, now.Unix()), true
+}
+
+func (s *Service) ratePath(ipHash string) string {
+	return "commentRateLimits/" + s.env() + "/" + ipHash
+}
@@ -0,0 +1,524 @@
+package comment
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	emailsvc "in-server/internal/service/email"
+	postsvc "in-server/internal/service/post"
+	"in-server/pkg/apperr"
+	"in-server/pkg/config"
+	"in-server/pkg/crypto"
+	"in-server/pkg/firebase"
+)
+
+const maxNameLength = 50
+
+type Status string
+
+const (
+	StatusUnverified Status = "unverified"
+	StatusPending    Status = "pending"
+	StatusApproved   Status = "approved"
+	StatusHidden     Status = "hidden"
+)
+
+type Action string
+
+const (
+	ActionApprove Action = "approve"
+	ActionHide    Action = "hide"
+	ActionDelete  Action = "delete"
+)
+
+type Comment struct {
+	ID         string    `json:"id"`
+	TokenID    string    `json:"tokenId"`
+	ParentID   string    `json:"parentId,omitempty"`
+	Depth      int       `json:"depth"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+	Status     Status    `json:"status"`
+	CreatedAt  string    `json:"createdAt"`
+	VerifiedAt string    `json:"verifiedAt,omitempty"`
+	Replies    []Comment `json:"replies,omitempty"`
+
+	// Only hashes of the commenter's email, IP and PIN are stored. They are
+	// stripped before comments leave the service.
+	EmailHash string `json:"emailHash,omitempty"`
+	IPHash    string `json:"ipHash,omitempty"`
+	PinHash   string `json:"pinHash,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`
+}
+
+func (c Comment) public() Comment {
+	c.EmailHash = ""
+	c.IPHash = ""
+	c.PinHash = ""
+	c.Attempts = 0
+	return c
+}
+
+type CreateInput struct {
+	ParentID string
+	Name     string
+	Email    string
+	Body     string
+}
+
+// pinVerifier is the subset of email.Service used to confirm a commenter's
+// address with the subscriber PIN flow.
+type pinVerifier interface {
+	ClaimPinCode(ctx context.Context, pinCode, recipientEmail string) error
+	VerifyPinCode(ctx context.Context, pinCode string) (bool, error)
+}
+
+// postChecker is the subset of post.Service used to reject comments on
+// tokens that were never minted.
+type postChecker interface {
+	Exists(ctx context.Context, tokenID string) error
+}
+
+type Service struct {
+	cfg   config.Config
+	fb    *firebase.Client
+	pins  pinVerifier
+	posts postChecker
+}
+
+func New(ctx context.Context, cfg config.Config, pins *emailsvc.Service, posts *postsvc.Service) (*Service, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	fbClient, err := firebase.New(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init firebase: %w", err)
+	}
+
+	return &Service{cfg: cfg, fb: fbClient, pins: pins, posts: posts}, nil
+}
+
+// Create stores an unverified comment on the post and mails a PIN to the
+// commenter. It only enters the moderation queue once Verify succeeds.
+func (s *Service) Create(ctx context.Context, tokenID string, in CreateInput, ip string) (Comment, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tokenID, err := validTokenID(tokenID)
+	if err != nil {
+		return Comment{}, err
+	}
+	in, err = s.validateInput(in)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	if err := s.posts.Exists(ctx, tokenID); err != nil {
+		return Comment{}, err
+	}
+
+	ipHash := s.ipHash(ip)
+	if err := s.checkRate(ctx, ipHash); err != nil {
+		return Comment{}, err
+	}
+
+	depth := 0
+	if in.ParentID != "" {
+		parent, err := s.read(ctx, tokenID, in.ParentID)
+		if err != nil {
+			if errors.Is(err, apperr.Comment.ErrCommentNotFound) {
+				return Comment{}, apperr.Comment.ErrInvalidParent
+			}
+			return Comment{}, err
+		}
+		if parent.Status != StatusApproved || parent.Depth+1 > s.maxDepth() {
+			return Comment{}, apperr.Comment.ErrInvalidParent
+		}
+		depth = parent.Depth + 1
+	}
+
+	id, err := newCommentID()
+	if err != nil {
+		return Comment{}, err
+	}
+	pinCode, err := emailsvc.GenerateFourDigitCode()
+	if err != nil {
+		return Comment{}, err
+	}
+
+	c := Comment{
+		ID:        id,
+		TokenID:   tokenID,
+		ParentID:  in.ParentID,
+		Depth:     depth,
+		Name:      in.Name,
+		Body:      in.Body,
+		Status:    StatusUnverified,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		EmailHash: crypto.SHA256(strings.ToLower(in.Email)),
+		IPHash:    ipHash,
+		PinHash:   pinHash(id, pinCode),
+	}
+	if err := s.write(ctx, c); err != nil {
+		return Comment{}, err
+	}
+
+	if err := s.pins.ClaimPinCode(ctx, pinCode, in.Email); err != nil {
+		_ = firebase.Write(ctx, s.fb, s.commentPath(tokenID, id), nil)
+		return Comment{}, err
+	}
+	return c.public(), nil
+}
+
+// Verify confirms the commenter's email with the PIN sent by Create and moves
+// the comment to the moderation queue. Repeated wrong PINs discard it.
+func (s *Service) Verify(ctx context.Context, tokenID, id, pinCode string) (Comment, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tokenID, err := validTokenID(tokenID)
+	if err != nil {
+		return Comment{}, err
+	}
+	c, err := s.read(ctx, tokenID, id)
+	if err != nil {
+		return Comment{}, err
+	}
+	if c.Status != StatusUnverified {
+		return Comment{}, apperr.Comment.ErrAlreadyVerified
+	}
+
+	pinCode = strings.TrimSpace(pinCode)
+	if subtle.ConstantTimeCompare([]byte(pinHash(c.ID, pinCode)), []byte(c.PinHash)) != 1 {
+		return Comment{}, s.failAttempt(ctx, c)
+	}
+	verified, err := s.pins.VerifyPinCode(ctx, pinCode)
+	if err != nil {
+		return Comment{}, err
+	}
+	if !verified {
+		return Comment{}, s.failAttempt(ctx, c)
+	}
+
+	c.Status = StatusPending
+	c.PinHash = ""
+	c.Attempts = 0
+	c.VerifiedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := s.write(ctx, c); err != nil {
+		return Comment{}, err
+	}
+	return c.public(), nil
+}
+
+func (s *Service) failAttempt(ctx context.Context, c Comment) error {
+	c.Attempts++
+	maxAttempts := s.cfg.Comments.MaxAttempts
+	if maxAttempts > 0 && c.Attempts >= maxAttempts {
+		if err := firebase.Write(ctx, s.fb, s.commentPath(c.TokenID, c.ID), nil); err != nil {
+			return apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+		}
+		return apperr.Comment.ErrTooManyAttempts
+	}
+	if err := s.write(ctx, c); err != nil {
+		return err
+	}
+	return apperr.Comment.ErrInvalidPinCode
+}
+
+// Thread returns the post's approved comments as a tree, oldest first.
+func (s *Service) Thread(ctx context.Context, tokenID string) ([]Comment, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tokenID, err := validTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.readPost(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	approved := make([]Comment, 0, len(comments))
+	for _, c := range comments {
+		if c.Status == StatusApproved {
+			approved = append(approved, c.public())
+		}
+	}
+	return buildThread(approved), nil
+}
+
+// Queue lists comments across all posts with the given status for
+// moderation, oldest first. An empty status means pending.
+func (s *Service) Queue(ctx context.Context, adminCode string, status Status) ([]Comment, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := s.Authorize(adminCode); err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = StatusPending
+	}
+
+	all, exists, err := firebase.Read[map[string]map[string]Comment](ctx, s.fb, s.rootPath())
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+	}
+	out := []Comment{}
+	if !exists {
+		return out, nil
+	}
+	for _, comments := range all {
+		for _, c := range comments {
+			if c.Status != status {
+				continue
+			}
+			out = append(out, c.public())
+		}
+	}
+	sortByCreated(out)
+	return out, nil
+}
+
+// Moderate approves, hides or deletes a comment. Deleting also removes every
+// reply beneath it.
+func (s *Service) Moderate(ctx context.Context, adminCode, tokenID, id string, action Action) (Comment, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := s.Authorize(adminCode); err != nil {
+		return Comment{}, err
+	}
+
+	tokenID, err := validTokenID(tokenID)
+	if err != nil {
+		return Comment{}, err
+	}
+	c, err := s.read(ctx, tokenID, id)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	switch action {
+	case ActionApprove, ActionHide:
+		if c.Status == StatusUnverified {
+			return Comment{}, apperr.Comment.ErrCommentNotFound
+		}
+		c.Status = StatusApproved
+		if action == ActionHide {
+			c.Status = StatusHidden
+		}
+		if err := s.write(ctx, c); err != nil {
+			return Comment{}, err
+		}
+	case ActionDelete:
+		comments, err := s.readPost(ctx, tokenID)
+		if err != nil {
+			return Comment{}, err
+		}
+		for _, cid := range descendants(comments, c.ID) {
+			if err := firebase.Write(ctx, s.fb, s.commentPath(tokenID, cid), nil); err != nil {
+				return Comment{}, apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+			}
+		}
+	default:
+		return Comment{}, apperr.Comment.ErrInvalidAction
+	}
+	return c.public(), nil
+}
+
+// Authorize checks adminCode against the configured admin code.
+func (s *Service) Authorize(adminCode string) error {
+	return s.cfg.AuthorizeAdmin(adminCode)
+}
+
+func (s *Service) validateInput(in CreateInput) (CreateInput, error) {
+	in.ParentID = strings.TrimSpace(in.ParentID)
+	in.Name = strings.TrimSpace(in.Name)
+	in.Body = strings.TrimSpace(in.Body)
+	in.Email = strings.TrimSpace(in.Email)
+
+	maxLength := s.cfg.Comments.MaxLength
+	if maxLength <= 0 {
+		maxLength = 2000
+	}
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > maxNameLength {
+		return CreateInput{}, apperr.Comment.ErrInvalidComment
+	}
+	if in.Body == "" || utf8.RuneCountInString(in.Body) > maxLength {
+		return CreateInput{}, apperr.Comment.ErrInvalidComment
+	}
+	addr, err := mail.ParseAddress(in.Email)
+	if err != nil || addr.Address != in.Email {
+		return CreateInput{}, apperr.Email.ErrInvalidEmail
+	}
+	if in.ParentID != "" && !validID(in.ParentID) {
+		return CreateInput{}, apperr.Comment.ErrInvalidParent
+	}
+	return in, nil
+}
+
+func (s *Service) maxDepth() int {
+	if s.cfg.Comments.MaxDepth <= 0 {
+		return 0
+	}
+	return s.cfg.Comments.MaxDepth
+}
+
+// ipHash salts the address with the auth hash so stored hashes cannot be
+// reversed by enumerating the IPv4 space.
+func (s *Service) ipHash(ip string) string {
+	return crypto.SHA256(s.cfg.Auth.Hash + ":" + strings.TrimSpace(ip))
+}
+
+func (s *Service) env() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return env
+}
+
+func (s *Service) rootPath() string {
+	return "comments/" + s.env()
+}
+
+func (s *Service) postPath(tokenID string) string {
+	return s.rootPath() + "/" + tokenID
+}
+
+func (s *Service) commentPath(tokenID, id string) string {
+	return s.postPath(tokenID) + "/" + id
+}
+
+func (s *Service) readPost(ctx context.Context, tokenID string) (map[string]Comment, error) {
+	comments, exists, err := firebase.Read[map[string]Comment](ctx, s.fb, s.postPath(tokenID))
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+	}
+	if !exists {
+		return map[string]Comment{}, nil
+	}
+	return comments, nil
+}
+
+func (s *Service) read(ctx context.Context, tokenID, id string) (Comment, error) {
+	id = strings.TrimSpace(id)
+	if !validID(id) {
+		return Comment{}, apperr.Comment.ErrCommentNotFound
+	}
+	c, exists, err := firebase.Read[Comment](ctx, s.fb, s.commentPath(tokenID, id))
+	if err != nil {
+		return Comment{}, apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+	}
+	if !exists {
+		return Comment{}, apperr.Comment.ErrCommentNotFound
+	}
+	return c, nil
+}
+
+func (s *Service) write(ctx context.Context, c Comment) error {
+	c.Replies = nil
+	if err := firebase.Write(ctx, s.fb, s.commentPath(c.TokenID, c.ID), c); err != nil {
+		return apperr.Wrap(err, apperr.Comment.ErrStorage.Code, apperr.Comment.ErrStorage.Message, apperr.Comment.ErrStorage.Status)
+	}
+	return nil
+}
+
+// buildThread nests replies under their parents. Replies whose parent is not
+// in comments (hidden or deleted) are dropped along with their subtree.
+func buildThread(comments []Comment) []Comment {
+	children := make(map[string][]Comment, len(comments))
+	for _, c := range comments {
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var attach func(parentID string) []Comment
+	attach = func(parentID string) []Comment {
+		nodes := children[parentID]
+		sortByCreated(nodes)
+		for i := range nodes {
+			nodes[i].Replies = attach(nodes[i].ID)
+		}
+		return nodes
+	}
+
+	roots := attach("")
+	if roots == nil {
+		roots = []Comment{}
+	}
+	return roots
+}
+
+// descendants returns id followed by the IDs of every reply beneath it.
+func descendants(comments map[string]Comment, id string) []string {
+	out := []string{id}
+	for i := 0; i < len(out); i++ {
+		for cid, c := range comments {
+			if c.ParentID == out[i] {
+				out = append(out, cid)
+			}
+		}
+	}
+	return out
+}
+
+func sortByCreated(comments []Comment) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].CreatedAt != comments[j].CreatedAt {
+			return comments[i].CreatedAt < comments[j].CreatedAt
+		}
+		return comments[i].ID < comments[j].ID
+	})
+}
+
+func validTokenID(tokenID string) (string, error) {
+	tokenID = strings.TrimSpace(tokenID)
+	if tokenID == "" {
+		return "", apperr.Comment.ErrInvalidPost
+	}
+	for _, r := range tokenID {
+		if r < '0' || r > '9' {
+			return "", apperr.Comment.ErrInvalidPost
+		}
+	}
+	return tokenID, nil
+}
+
+func validID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func pinHash(id, pinCode string) string {
+	return crypto.SHA256(id + ":" + pinCode)
+}
+
+func newCommentID() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate comment id: %w", err)
+	}
+	return fmt.Sprintf("c-%d-%s", time.Now().UTC().Unix(), hex.EncodeToString(buf)), nil
+}
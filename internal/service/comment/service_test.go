@@ -0,0 +1,74 @@
+package comment
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestBuildThreadNestsAndDropsOrphans(t *testing.T) {
+	comments := []Comment{
+		{ID: "b", CreatedAt: "2024-01-02T00:00:00Z"},
+		{ID: "a", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: "a1", ParentID: "a", CreatedAt: "2024-01-03T00:00:00Z"},
+		{ID: "a1x", ParentID: "a1", CreatedAt: "2024-01-04T00:00:00Z"},
+		{ID: "orphan", ParentID: "hidden", CreatedAt: "2024-01-01T00:00:00Z"},
+	}
+
+	roots := buildThread(comments)
+	if len(roots) != 2 || roots[0].ID != "a" || roots[1].ID != "b" {
+		t.Fatalf("roots = %+v", roots)
+	}
+	if len(roots[0].Replies) != 1 || roots[0].Replies[0].ID != "a1" {
+		t.Fatalf("replies of a = %+v", roots[0].Replies)
+	}
+	if got := roots[0].Replies[0].Replies; len(got) != 1 || got[0].ID != "a1x" {
+		t.Fatalf("replies of a1 = %+v", got)
+	}
+	if len(buildThread(nil)) != 0 {
+		t.Fatalf("empty thread should be an empty slice")
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	comments := map[string]Comment{
+		"a":   {ID: "a"},
+		"a1":  {ID: "a1", ParentID: "a"},
+		"a2":  {ID: "a2", ParentID: "a"},
+		"a1x": {ID: "a1x", ParentID: "a1"},
+		"b":   {ID: "b"},
+	}
+	got := descendants(comments, "a")
+	sort.Strings(got)
+	want := []string{"a", "a1", "a1x", "a2"}
+	if len(got) != len(want) {
+		t.Fatalf("descendants = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("descendants = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestAllowAttempt(t *testing.T) {
+	now := time.Unix(10_000, 0)
+	window := 10 * time.Minute
+	old := now.Add(-11 * time.Minute).Unix()
+	recent := now.Add(-time.Minute).Unix()
+
+	kept, ok := allowAttempt([]int64{old, recent}, now, 2, window)
+	if !ok || len(kept) != 2 || kept[0] != recent || kept[1] != now.Unix() {
+		t.Fatalf("allowAttempt = %v, %v", kept, ok)
+	}
+	if _, ok := allowAttempt(kept, now, 2, window); ok {
+		t.Fatalf("third attempt inside the window should be rejected")
+	}
+}
+
+func TestPublicStripsHashes(t *testing.T) {
+	c := Comment{ID: "c", EmailHash: "e", IPHash: "i", PinHash: "p", Attempts: 2}.public()
+	if c.EmailHash != "" || c.IPHash != "" || c.PinHash != "" || c.Attempts != 0 {
+		t.Fatalf("public() leaked fields: %+v", c)
+	}
+}
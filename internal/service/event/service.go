@@ -0,0 +1,97 @@
+package event
+
+import (
+	"context"
+	"fmt"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/config"
+	"in-server/pkg/eth"
+	"in-server/pkg/types"
+)
+
+const MaxListLimit = 500
+
+// IndexedContracts are the contracts whose logs the indexer follows.
+var IndexedContracts = []types.ContractName{
+	types.POSTSTORAGE,
+	types.SUBSCRIBERSTORAGE,
+	types.VISITORSTORAGE,
+}
+
+type Service struct {
+	cfg     config.Config
+	store   eth.EventStore
+	indexer *eth.Indexer
+}
+
+// New returns a disabled service when INDEXER_ENABLED is off, so handlers and
+// jobs can be wired unconditionally.
+func New(ctx context.Context, cfg config.Config) (*Service, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !cfg.Indexer.Enabled {
+		return &Service{cfg: cfg}, nil
+	}
+
+	ethClient, err := eth.Dial(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dial eth client: %w", err)
+	}
+
+	store, err := eth.OpenFileStore(cfg.Indexer.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("open event store: %w", err)
+	}
+
+	indexer, err := ethClient.NewIndexer(store, eth.IndexerOptions{
+		StartBlock:    cfg.Indexer.StartBlock,
+		BatchSize:     cfg.Indexer.BatchSize,
+		Confirmations: cfg.Indexer.Confirmations,
+	}, IndexedContracts...)
+	if err != nil {
+		return nil, fmt.Errorf("init event indexer: %w", err)
+	}
+
+	return &Service{cfg: cfg, store: store, indexer: indexer}, nil
+}
+
+func (s *Service) Enabled() bool { return s != nil && s.indexer != nil }
+
+// Sync pulls new logs into the store. It is a no-op when disabled.
+func (s *Service) Sync(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+	return s.indexer.Sync(ctx)
+}
+
+// Events queries indexed events for other services.
+func (s *Service) Events(ctx context.Context, filter eth.EventFilter) ([]eth.Event, error) {
+	if !s.Enabled() {
+		return nil, apperr.Event.ErrIndexerDisabled
+	}
+	if filter.Limit <= 0 || filter.Limit > MaxListLimit {
+		filter.Limit = MaxListLimit
+	}
+	events, err := s.store.Events(ctx, filter)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.Event.ErrLoadEvents.Code, apperr.Event.ErrLoadEvents.Message, apperr.Event.ErrLoadEvents.Status)
+	}
+	return events, nil
+}
+
+// Checkpoint returns the last fully indexed block.
+func (s *Service) Checkpoint(ctx context.Context) (uint64, error) {
+	if !s.Enabled() {
+		return 0, apperr.Event.ErrIndexerDisabled
+	}
+	block, _, err := s.store.Checkpoint(ctx)
+	return block, err
+}
+
+// Authorize checks adminCode against the configured admin code.
+func (s *Service) Authorize(adminCode string) error {
+	return s.cfg.AuthorizeAdmin(adminCode)
+}
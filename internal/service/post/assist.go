@@ -0,0 +1,104 @@
+package post
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/markdown"
+)
+
+const (
+	maxAssistContentRunes = 20000
+	maxSuggestedTags      = 8
+)
+
+// AssistSuggestion holds Gemini's suggestions as NftMetadata attributes, so
+// editors can merge them straight into a draft payload.
+type AssistSuggestion struct {
+	Attributes []NftAttribute `json:"attributes"`
+}
+
+type assistOutput struct {
+	Summary string   `json:"summary"`
+	Tags    []string `json:"tags"`
+	Slug    string   `json:"slug"`
+}
+
+// Assist suggests a summary, tags and slug for a draft. Every call is billed
+// to the server's Gemini key, so only the server admin may use it.
+func (s *Service) Assist(ctx context.Context, adminCode, title, content string) (AssistSuggestion, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := s.AuthorizeServerAdmin(adminCode); err != nil {
+		return AssistSuggestion{}, err
+	}
+
+	plain := markdown.PlainText(content)
+	title = strings.TrimSpace(title)
+	if plain == "" && title == "" {
+		return AssistSuggestion{}, apperr.Post.ErrInvalidBody
+	}
+	if utf8.RuneCountInString(plain) > maxAssistContentRunes {
+		plain = string([]rune(plain)[:maxAssistContentRunes])
+	}
+
+	var out assistOutput
+	if err := s.gemini.GenerateJSON(ctx, assistPrompt(title, plain), &out); err != nil {
+		return AssistSuggestion{}, err
+	}
+	return suggestionAttributes(out, title), nil
+}
+
+func assistPrompt(title, content string) string {
+	return strings.Join([]string{
+		"You help editors of a tech blog whose posts mix Korean and English.",
+		"Read the post below and answer with a JSON object with exactly these keys:",
+		`- "summary": one or two sentences, at most 160 characters, in the post's main language`,
+		fmt.Sprintf(`- "tags": up to %d short lowercase topic tags without spaces or '#'`, maxSuggestedTags),
+		`- "slug": a short lowercase ASCII kebab-case URL slug in English`,
+		"",
+		"Title: " + title,
+		"",
+		content,
+	}, "\n")
+}
+
+func suggestionAttributes(out assistOutput, title string) AssistSuggestion {
+	var attrs []NftAttribute
+
+	if summary := truncateRunes(strings.Join(strings.Fields(out.Summary), " "), summaryMaxRunes); summary != "" {
+		attrs = append(attrs, NftAttribute{TraitType: "Summary", Value: json.RawMessage(mustJSONQuote(summary))})
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range out.Tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(strings.TrimLeft(tag, "#")), "-"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == maxSuggestedTags {
+			break
+		}
+	}
+	if len(tags) > 0 {
+		attrs = append(attrs, NftAttribute{TraitType: "Tags", Value: json.RawMessage(mustJSONQuote(strings.Join(tags, " ")))})
+	}
+
+	slug := toPathSegment(out.Slug)
+	if slug == "" {
+		slug = toPathSegment(title)
+	}
+	if slug != "" {
+		attrs = append(attrs, NftAttribute{TraitType: "Slug", Value: json.RawMessage(mustJSONQuote(slug))})
+	}
+
+	return AssistSuggestion{Attributes: attrs}
+}
@@ -0,0 +1,188 @@
+package post
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/firebase"
+)
+
+const (
+	maxDisplayNameRunes = 50
+	maxBioRunes         = 500
+)
+
+// AuthorProfile is the off-chain profile shown next to a wallet's posts.
+type AuthorProfile struct {
+	Address     string `json:"address"`
+	DisplayName string `json:"displayName,omitempty"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+}
+
+func (s *Service) authorsRoot() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "authors/" + env
+}
+
+func (s *Service) authorPath(addr common.Address) string {
+	return s.authorsRoot() + "/" + addr.Hex()
+}
+
+// Author returns the stored profile for addr, or a bare profile holding only
+// the address when none has been saved.
+func (s *Service) Author(ctx context.Context, addr common.Address) (AuthorProfile, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return AuthorProfile{}, fmt.Errorf("firebase client is nil")
+	}
+
+	profile, exists, err := firebase.Read[AuthorProfile](ctx, s.fb, s.authorPath(addr))
+	if err != nil {
+		return AuthorProfile{}, fmt.Errorf("read author profile: %w", err)
+	}
+	if !exists {
+		profile = AuthorProfile{}
+	}
+	profile.Address = addr.Hex()
+	return profile, nil
+}
+
+// SaveAuthor stores a profile. The admin code must either derive addr's
+// wallet, so authors manage their own profile, or be the server admin code.
+func (s *Service) SaveAuthor(ctx context.Context, adminCode string, addr common.Address, profile AuthorProfile) (AuthorProfile, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return AuthorProfile{}, fmt.Errorf("firebase client is nil")
+	}
+
+	walletAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return AuthorProfile{}, err
+	}
+	if walletAddr != addr && !s.isServerAdmin(adminCode) {
+		return AuthorProfile{}, apperr.Post.ErrNotAuthor
+	}
+
+	profile.DisplayName = strings.Join(strings.Fields(profile.DisplayName), " ")
+	profile.Bio = strings.TrimSpace(profile.Bio)
+	profile.AvatarURL = strings.TrimSpace(profile.AvatarURL)
+	if utf8.RuneCountInString(profile.DisplayName) > maxDisplayNameRunes ||
+		utf8.RuneCountInString(profile.Bio) > maxBioRunes {
+		return AuthorProfile{}, apperr.Post.ErrInvalidAuthor
+	}
+	if profile.AvatarURL != "" {
+		u, err := url.Parse(profile.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return AuthorProfile{}, apperr.Post.ErrInvalidAuthor
+		}
+	}
+
+	profile.Address = addr.Hex()
+	profile.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := firebase.Write(ctx, s.fb, s.authorPath(addr), profile); err != nil {
+		return AuthorProfile{}, fmt.Errorf("write author profile: %w", err)
+	}
+	return profile, nil
+}
+
+// isServerAdmin reports whether adminCode is the configured server admin
+// code rather than just any code that derives a wallet.
+func (s *Service) isServerAdmin(adminCode string) bool {
+	return s.cfg.AuthorizeAdmin(adminCode) == nil
+}
+
+// AuthorizeServerAdmin guards operator endpoints that expose internals; a
+// code that merely derives a wallet is not enough.
+func (s *Service) AuthorizeServerAdmin(adminCode string) error {
+	return s.cfg.AuthorizeAdmin(adminCode)
+}
+
+func (s *Service) publishersPath() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "publishers/" + env
+}
+
+// rememberPublisher records a wallet that holds posts so slug lookups can
+// search it. PostStorage cannot enumerate holders, so this is best effort.
+func (s *Service) rememberPublisher(ctx context.Context, addr common.Address) {
+	if s.fb == nil || addr == (common.Address{}) {
+		return
+	}
+	if err := firebase.Write(ctx, s.fb, s.publishersPath()+"/"+addr.Hex(), true); err != nil {
+		log.Printf("post publishers %s: %v", addr.Hex(), err)
+	}
+}
+
+// knownOwners lists the wallets a slug may belong to: the server admin
+// wallet first, then every wallet rememberPublisher recorded. Author profiles
+// are not consulted, since anyone can save one without holding a post.
+func (s *Service) knownOwners(ctx context.Context) ([]common.Address, error) {
+	adminAddr, err := s.ownerAddress()
+	if err != nil {
+		return nil, err
+	}
+	out := []common.Address{adminAddr}
+	if s.fb == nil {
+		return out, nil
+	}
+
+	seen := map[common.Address]bool{adminAddr: true}
+	add := func(raw string) {
+		if !common.IsHexAddress(raw) {
+			return
+		}
+		addr := common.HexToAddress(raw)
+		if !seen[addr] {
+			seen[addr] = true
+			out = append(out, addr)
+		}
+	}
+
+	publishers, _, err := firebase.Read[map[string]bool](ctx, s.fb, s.publishersPath())
+	if err != nil {
+		return nil, fmt.Errorf("read publishers: %w", err)
+	}
+	for raw := range publishers {
+		add(raw)
+	}
+	sort.Slice(out[1:], func(i, j int) bool { return out[1+i].Hex() < out[1+j].Hex() })
+	return out, nil
+}
+
+// attachAuthor sets the owner's profile on posts. A missing profile store is
+// not worth failing a listing over, so errors are only logged.
+func (s *Service) attachAuthor(ctx context.Context, owner common.Address, posts []Post) {
+	if len(posts) == 0 || s.fb == nil {
+		return
+	}
+	profile, err := s.Author(ctx, owner)
+	if err != nil {
+		log.Printf("post author %s: %v", owner.Hex(), err)
+		return
+	}
+	for i := range posts {
+		p := profile
+		posts[i].Author = &p
+	}
+}
@@ -0,0 +1,196 @@
+package post
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type CacheStats struct {
+	MetadataEntries     int    `json:"metadataEntries"`
+	MetadataHits        uint64 `json:"metadataHits"`
+	MetadataMisses      uint64 `json:"metadataMisses"`
+	MetadataRevalidated uint64 `json:"metadataRevalidated"`
+	ListEntries         int    `json:"listEntries"`
+	ListHits            uint64 `json:"listHits"`
+	ListMisses          uint64 `json:"listMisses"`
+}
+
+type metadataEntry struct {
+	meta         metadata
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+}
+
+type listEntry struct {
+	posts     []Post
+	fetchedAt time.Time
+}
+
+// postCache keeps fetched metadata documents keyed by URL and the mapped post
+// list per owner. Metadata entries past their TTL are revalidated with
+// ETag/Last-Modified instead of being dropped.
+type postCache struct {
+	metadataTTL time.Duration
+	listTTL     time.Duration
+
+	mu       sync.RWMutex
+	metadata map[string]metadataEntry
+	lists    map[common.Address]listEntry
+	related  map[common.Address]*relatedIndex
+
+	metadataHits        atomic.Uint64
+	metadataMisses      atomic.Uint64
+	metadataRevalidated atomic.Uint64
+	listHits            atomic.Uint64
+	listMisses          atomic.Uint64
+}
+
+func newPostCache(metadataTTL, listTTL time.Duration) *postCache {
+	return &postCache{
+		metadataTTL: metadataTTL,
+		listTTL:     listTTL,
+		metadata:    make(map[string]metadataEntry),
+		lists:       make(map[common.Address]listEntry),
+		related:     make(map[common.Address]*relatedIndex),
+	}
+}
+
+// getMetadata returns the cached entry and whether it is still fresh. Fresh
+// lookups count as hits.
+func (c *postCache) getMetadata(url string) (metadataEntry, bool, bool) {
+	if c == nil {
+		return metadataEntry{}, false, false
+	}
+	c.mu.RLock()
+	entry, ok := c.metadata[url]
+	c.mu.RUnlock()
+	if !ok {
+		return metadataEntry{}, false, false
+	}
+	fresh := c.metadataTTL > 0 && time.Since(entry.fetchedAt) < c.metadataTTL
+	if fresh {
+		c.metadataHits.Add(1)
+	}
+	return entry, true, fresh
+}
+
+func (c *postCache) putMetadata(url string, entry metadataEntry) {
+	if c == nil {
+		return
+	}
+	c.metadataMisses.Add(1)
+	c.mu.Lock()
+	c.metadata[url] = entry
+	c.mu.Unlock()
+}
+
+// revalidateMetadata marks a stale entry fresh again after a 304 response.
+func (c *postCache) revalidateMetadata(url string) {
+	if c == nil {
+		return
+	}
+	c.metadataRevalidated.Add(1)
+	c.mu.Lock()
+	if entry, ok := c.metadata[url]; ok {
+		entry.fetchedAt = time.Now()
+		c.metadata[url] = entry
+	}
+	c.mu.Unlock()
+}
+
+func (c *postCache) getList(owner common.Address) ([]Post, bool) {
+	if c == nil || c.listTTL <= 0 {
+		return nil, false
+	}
+	c.mu.RLock()
+	entry, ok := c.lists[owner]
+	c.mu.RUnlock()
+	if !ok || time.Since(entry.fetchedAt) >= c.listTTL {
+		c.listMisses.Add(1)
+		return nil, false
+	}
+	c.listHits.Add(1)
+	return clonePosts(entry.posts), true
+}
+
+func (c *postCache) putList(owner common.Address, posts []Post) {
+	if c == nil || c.listTTL <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.lists[owner] = listEntry{posts: clonePosts(posts), fetchedAt: time.Now()}
+	c.mu.Unlock()
+}
+
+// invalidate drops the owner's cached list and the given metadata URLs.
+func (c *postCache) invalidate(owner common.Address, urls ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.lists, owner)
+	delete(c.related, owner)
+	for _, u := range urls {
+		delete(c.metadata, u)
+	}
+	c.mu.Unlock()
+}
+
+// getRelated returns the owner's related-post index if it was built from the
+// listing identified by fingerprint.
+func (c *postCache) getRelated(owner common.Address, fingerprint string) (*relatedIndex, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	idx, ok := c.related[owner]
+	c.mu.RUnlock()
+	if !ok || idx.fingerprint != fingerprint {
+		return nil, false
+	}
+	return idx, true
+}
+
+func (c *postCache) putRelated(owner common.Address, idx *relatedIndex) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.related[owner] = idx
+	c.mu.Unlock()
+}
+
+func (c *postCache) stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.RLock()
+	metadataEntries, listEntries := len(c.metadata), len(c.lists)
+	c.mu.RUnlock()
+	return CacheStats{
+		MetadataEntries:     metadataEntries,
+		MetadataHits:        c.metadataHits.Load(),
+		MetadataMisses:      c.metadataMisses.Load(),
+		MetadataRevalidated: c.metadataRevalidated.Load(),
+		ListEntries:         listEntries,
+		ListHits:            c.listHits.Load(),
+		ListMisses:          c.listMisses.Load(),
+	}
+}
+
+func clonePosts(posts []Post) []Post {
+	if posts == nil {
+		return nil
+	}
+	out := make([]Post, len(posts))
+	copy(out, posts)
+	return out
+}
+
+func (s *Service) CacheStats() CacheStats {
+	return s.cache.stats()
+}
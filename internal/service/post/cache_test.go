@@ -0,0 +1,54 @@
+package post
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchMetadataRevalidatesWithETag(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"name":"hello","attributes":[{"trait_type":"Slug","value":"hello"}]}`))
+	}))
+	defer srv.Close()
+
+	s := &Service{httpClient: srv.Client(), cache: newPostCache(time.Hour, time.Minute)}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		meta, err := s.fetchMetadata(ctx, srv.URL)
+		if err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+		if meta.Name != "hello" {
+			t.Fatalf("unexpected name: %q", meta.Name)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("fresh entry should not hit origin, got %d requests", got)
+	}
+
+	s.cache.metadataTTL = 0
+	if _, err := s.fetchMetadata(ctx, srv.URL); err != nil {
+		t.Fatalf("revalidate: %v", err)
+	}
+	if notModified.Load() != 1 {
+		t.Fatalf("expected conditional request to be answered with 304")
+	}
+
+	stats := s.CacheStats()
+	if stats.MetadataHits != 1 || stats.MetadataMisses != 1 || stats.MetadataRevalidated != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
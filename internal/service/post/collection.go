@@ -0,0 +1,420 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/firebase"
+)
+
+const (
+	maxCollectionTitleRunes       = 120
+	maxCollectionDescriptionRunes = 1000
+	maxCollectionPosts            = 200
+)
+
+type CollectionKind string
+
+const (
+	// CollectionSeries is an ordered multi-part series; its posts get
+	// previous/next navigation.
+	CollectionSeries CollectionKind = "series"
+	// CollectionCurated is a hand-picked list without navigation.
+	CollectionCurated CollectionKind = "collection"
+)
+
+type Collection struct {
+	ID          string         `json:"id"`
+	Kind        CollectionKind `json:"kind"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	CoverURL    string         `json:"coverUrl,omitempty"`
+	TokenIDs    []string       `json:"tokenIds"`
+	Owner       string         `json:"owner"`
+	CreatedAt   string         `json:"createdAt"`
+	UpdatedAt   string         `json:"updatedAt"`
+}
+
+type CollectionDetail struct {
+	Collection
+	Posts []Post `json:"posts"`
+}
+
+type SeriesLink struct {
+	TokenID string `json:"tokenId"`
+	Title   string `json:"title,omitempty"`
+	Slug    string `json:"slug,omitempty"`
+	Href    string `json:"href,omitempty"`
+}
+
+// SeriesNav places a post within the series it belongs to.
+type SeriesNav struct {
+	CollectionID string      `json:"collectionId"`
+	Title        string      `json:"title"`
+	Position     int         `json:"position"`
+	Total        int         `json:"total"`
+	Previous     *SeriesLink `json:"previous,omitempty"`
+	Next         *SeriesLink `json:"next,omitempty"`
+}
+
+// Collections lists every collection, newest first.
+func (s *Service) Collections(ctx context.Context) ([]Collection, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	all, err := s.readCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Collection, 0, len(all))
+	for _, c := range all {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedAt != out[j].CreatedAt {
+			return out[i].CreatedAt > out[j].CreatedAt
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// Collection returns a collection with its posts in collection order. Each
+// token is resolved on its own, since a series may belong to any wallet.
+// Token IDs that no longer resolve to a post are skipped.
+func (s *Service) Collection(ctx context.Context, id string) (CollectionDetail, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c, err := s.readCollection(ctx, id)
+	if err != nil {
+		return CollectionDetail{}, err
+	}
+
+	byToken, err := s.postsByToken(ctx, c.TokenIDs, nil)
+	if err != nil {
+		return CollectionDetail{}, err
+	}
+
+	detail := CollectionDetail{Collection: c, Posts: make([]Post, 0, len(c.TokenIDs))}
+	for _, tokenID := range c.TokenIDs {
+		if p, ok := byToken[tokenID]; ok {
+			detail.Posts = append(detail.Posts, p)
+		}
+	}
+	return detail, nil
+}
+
+// SaveCollection creates a collection when id is empty and otherwise
+// replaces the stored one. Only the wallet that created a collection, or the
+// server admin code, may change it. Curated collections need the server
+// admin code, and a series may only hold the caller's own posts.
+func (s *Service) SaveCollection(ctx context.Context, adminCode, id string, in Collection) (Collection, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return Collection{}, fmt.Errorf("firebase client is nil")
+	}
+
+	walletAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return Collection{}, err
+	}
+	in, err = normalizeCollection(in)
+	if err != nil {
+		return Collection{}, err
+	}
+	// Curated collections are site editorial and hold anyone's posts, so
+	// only the server admin may publish one.
+	if in.Kind == CollectionCurated {
+		if err := s.AuthorizeServerAdmin(adminCode); err != nil {
+			return Collection{}, err
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if strings.TrimSpace(id) == "" {
+		if in.ID == "" {
+			in.ID = in.Title
+		}
+		in.ID = toPathSegment(in.ID)
+		if in.ID == "" {
+			return Collection{}, apperr.Post.ErrInvalidCollection
+		}
+		if _, err := s.readCollection(ctx, in.ID); err == nil {
+			return Collection{}, apperr.Post.ErrDuplicateCollection
+		} else if !errors.Is(err, apperr.Post.ErrCollectionNotFound) {
+			return Collection{}, err
+		}
+		in.Owner = walletAddr.Hex()
+		in.CreatedAt = now
+	} else {
+		existing, err := s.readCollection(ctx, id)
+		if err != nil {
+			return Collection{}, err
+		}
+		if existing.Owner != walletAddr.Hex() && !s.isServerAdmin(adminCode) {
+			return Collection{}, apperr.Post.ErrNotCollectionOwner
+		}
+		in.ID = existing.ID
+		in.Owner = existing.Owner
+		in.CreatedAt = existing.CreatedAt
+	}
+	in.UpdatedAt = now
+
+	// A series adds navigation to every post in it, so only the server
+	// admin may put posts held by another wallet into one.
+	if in.Kind == CollectionSeries && !s.isServerAdmin(adminCode) {
+		if err := s.checkTokensOwned(ctx, walletAddr, in.TokenIDs); err != nil {
+			return Collection{}, err
+		}
+	}
+
+	if err := firebase.Write(ctx, s.fb, s.collectionPath(in.ID), in); err != nil {
+		return Collection{}, fmt.Errorf("write collection: %w", err)
+	}
+	return in, nil
+}
+
+// DeleteCollection removes a collection. The posts themselves are untouched.
+func (s *Service) DeleteCollection(ctx context.Context, adminCode, id string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	walletAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return err
+	}
+	existing, err := s.readCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.Owner != walletAddr.Hex() && !s.isServerAdmin(adminCode) {
+		return apperr.Post.ErrNotCollectionOwner
+	}
+	if err := firebase.Write(ctx, s.fb, s.collectionPath(existing.ID), nil); err != nil {
+		return fmt.Errorf("delete collection: %w", err)
+	}
+	return nil
+}
+
+// attachSeries sets series navigation on posts that belong to a series. A
+// post in several series is placed in the one with the smallest ID. Like the
+// other decorations, failures are logged and the posts returned unchanged.
+func (s *Service) attachSeries(ctx context.Context, posts []Post) {
+	if len(posts) == 0 || s.fb == nil {
+		return
+	}
+	all, err := s.readCollections(ctx)
+	if err != nil {
+		log.Printf("post series: %v", err)
+		return
+	}
+
+	series := make([]Collection, 0, len(all))
+	for _, c := range all {
+		if c.Kind == CollectionSeries {
+			series = append(series, c)
+		}
+	}
+	if len(series) == 0 {
+		return
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].ID < series[j].ID })
+
+	for i := range posts {
+		c, pos, ok := findInSeries(series, posts[i].TokenID)
+		if !ok {
+			continue
+		}
+		var adjacent []string
+		if pos > 0 {
+			adjacent = append(adjacent, c.TokenIDs[pos-1])
+		}
+		if pos+1 < len(c.TokenIDs) {
+			adjacent = append(adjacent, c.TokenIDs[pos+1])
+		}
+		neighbors, err := s.postsByToken(ctx, adjacent, posts)
+		if err != nil {
+			log.Printf("post series: %v", err)
+			return
+		}
+		posts[i].Series = seriesNav(c, pos, neighbors)
+	}
+}
+
+func findInSeries(series []Collection, tokenID string) (Collection, int, bool) {
+	for _, c := range series {
+		for pos, id := range c.TokenIDs {
+			if id == tokenID {
+				return c, pos, true
+			}
+		}
+	}
+	return Collection{}, 0, false
+}
+
+func seriesNav(c Collection, pos int, posts map[string]Post) *SeriesNav {
+	nav := &SeriesNav{
+		CollectionID: c.ID,
+		Title:        c.Title,
+		Position:     pos + 1,
+		Total:        len(c.TokenIDs),
+	}
+	if pos > 0 {
+		nav.Previous = seriesLink(c.TokenIDs[pos-1], posts)
+	}
+	if pos+1 < len(c.TokenIDs) {
+		nav.Next = seriesLink(c.TokenIDs[pos+1], posts)
+	}
+	return nav
+}
+
+func seriesLink(tokenID string, posts map[string]Post) *SeriesLink {
+	link := &SeriesLink{TokenID: tokenID}
+	if p, ok := posts[tokenID]; ok {
+		link.Title = p.Title
+		link.Slug = p.Slug
+		link.Href = p.Href
+	}
+	return link
+}
+
+// postsByToken resolves tokenIDs through get, whichever wallet holds them,
+// reusing any already in known. Tokens that no longer exist are left out.
+func (s *Service) postsByToken(ctx context.Context, tokenIDs []string, known []Post) (map[string]Post, error) {
+	out := make(map[string]Post, len(tokenIDs))
+	for _, p := range known {
+		if slices.Contains(tokenIDs, p.TokenID) {
+			out[p.TokenID] = p
+		}
+	}
+	for _, tokenID := range tokenIDs {
+		if _, ok := out[tokenID]; ok {
+			continue
+		}
+		p, err := s.get(ctx, tokenID)
+		if errors.Is(err, apperr.Post.ErrPostNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[tokenID] = p
+	}
+	return out, nil
+}
+
+// checkTokensOwned returns apperr.Post.ErrNotPostOwner unless ownerAddr
+// holds every post in tokenIDs.
+func (s *Service) checkTokensOwned(ctx context.Context, ownerAddr common.Address, tokenIDs []string) error {
+	for _, raw := range tokenIDs {
+		tokenID, ok := parseTokenID(raw)
+		if !ok {
+			return apperr.Post.ErrInvalidCollection
+		}
+		holder, err := s.tokenOwner(ctx, tokenID)
+		if err != nil {
+			return err
+		}
+		if holder != ownerAddr {
+			return apperr.Post.ErrNotPostOwner
+		}
+	}
+	return nil
+}
+
+func normalizeCollection(c Collection) (Collection, error) {
+	c.ID = strings.TrimSpace(c.ID)
+	c.Title = strings.Join(strings.Fields(c.Title), " ")
+	c.Description = strings.TrimSpace(c.Description)
+	c.CoverURL = strings.TrimSpace(c.CoverURL)
+	if c.Kind == "" {
+		c.Kind = CollectionCurated
+	}
+
+	if c.Kind != CollectionSeries && c.Kind != CollectionCurated {
+		return Collection{}, apperr.Post.ErrInvalidCollection
+	}
+	if c.Title == "" || utf8.RuneCountInString(c.Title) > maxCollectionTitleRunes ||
+		utf8.RuneCountInString(c.Description) > maxCollectionDescriptionRunes {
+		return Collection{}, apperr.Post.ErrInvalidCollection
+	}
+	if c.CoverURL != "" {
+		u, err := url.Parse(c.CoverURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Collection{}, apperr.Post.ErrInvalidCollection
+		}
+	}
+	if len(c.TokenIDs) > maxCollectionPosts {
+		return Collection{}, apperr.Post.ErrInvalidCollection
+	}
+
+	seen := make(map[string]bool, len(c.TokenIDs))
+	tokenIDs := make([]string, 0, len(c.TokenIDs))
+	for _, raw := range c.TokenIDs {
+		id := strings.TrimSpace(raw)
+		if _, ok := parseTokenID(id); !ok || id == "" || seen[id] {
+			return Collection{}, apperr.Post.ErrInvalidCollection
+		}
+		seen[id] = true
+		tokenIDs = append(tokenIDs, id)
+	}
+	c.TokenIDs = tokenIDs
+	return c, nil
+}
+
+func (s *Service) collectionsPath() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "collections/" + env
+}
+
+func (s *Service) collectionPath(id string) string {
+	return s.collectionsPath() + "/" + id
+}
+
+func (s *Service) readCollections(ctx context.Context) (map[string]Collection, error) {
+	all, exists, err := firebase.Read[map[string]Collection](ctx, s.fb, s.collectionsPath())
+	if err != nil {
+		return nil, fmt.Errorf("read collections: %w", err)
+	}
+	if !exists {
+		return map[string]Collection{}, nil
+	}
+	return all, nil
+}
+
+func (s *Service) readCollection(ctx context.Context, id string) (Collection, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || toPathSegment(id) != id {
+		return Collection{}, apperr.Post.ErrCollectionNotFound
+	}
+	c, exists, err := firebase.Read[Collection](ctx, s.fb, s.collectionPath(id))
+	if err != nil {
+		return Collection{}, fmt.Errorf("read collection: %w", err)
+	}
+	if !exists {
+		return Collection{}, apperr.Post.ErrCollectionNotFound
+	}
+	if c.TokenIDs == nil {
+		c.TokenIDs = []string{}
+	}
+	return c, nil
+}
@@ -0,0 +1,67 @@
+package post
+
+import (
+	"errors"
+	"testing"
+
+	"in-server/pkg/apperr"
+)
+
+func TestNormalizeCollection(t *testing.T) {
+	c, err := normalizeCollection(Collection{
+		Title:    "  Go   서버 만들기 ",
+		TokenIDs: []string{" 3", "1 ", "2"},
+	})
+	if err != nil {
+		t.Fatalf("normalizeCollection: %v", err)
+	}
+	if c.Kind != CollectionCurated || c.Title != "Go 서버 만들기" {
+		t.Fatalf("normalized = %+v", c)
+	}
+	if len(c.TokenIDs) != 3 || c.TokenIDs[0] != "3" || c.TokenIDs[1] != "1" {
+		t.Fatalf("token ids = %v", c.TokenIDs)
+	}
+
+	invalid := []Collection{
+		{Title: ""},
+		{Title: "x", Kind: "playlist"},
+		{Title: "x", TokenIDs: []string{"1", "1"}},
+		{Title: "x", TokenIDs: []string{"abc"}},
+		{Title: "x", CoverURL: "ftp://cdn/cover.png"},
+	}
+	for _, in := range invalid {
+		if _, err := normalizeCollection(in); !errors.Is(err, apperr.Post.ErrInvalidCollection) {
+			t.Errorf("normalizeCollection(%+v) err = %v", in, err)
+		}
+	}
+}
+
+func TestSeriesNav(t *testing.T) {
+	series := []Collection{
+		{ID: "a-series", Kind: CollectionSeries, Title: "Part series", TokenIDs: []string{"5", "7", "9"}},
+	}
+	posts := map[string]Post{
+		"5": {TokenID: "5", Title: "Part 1", Href: "/lab/part-1"},
+		"9": {TokenID: "9", Title: "Part 3", Href: "/lab/part-3"},
+	}
+
+	c, pos, ok := findInSeries(series, "7")
+	if !ok {
+		t.Fatalf("token 7 not found in series")
+	}
+	nav := seriesNav(c, pos, posts)
+	if nav.Position != 2 || nav.Total != 3 || nav.CollectionID != "a-series" {
+		t.Fatalf("nav = %+v", nav)
+	}
+	if nav.Previous == nil || nav.Previous.Title != "Part 1" || nav.Next == nil || nav.Next.Href != "/lab/part-3" {
+		t.Fatalf("links = %+v / %+v", nav.Previous, nav.Next)
+	}
+
+	c, pos, _ = findInSeries(series, "5")
+	if first := seriesNav(c, pos, posts); first.Previous != nil || first.Next == nil || first.Next.TokenID != "7" {
+		t.Fatalf("first nav = %+v", first)
+	}
+	if _, _, ok := findInSeries(series, "1"); ok {
+		t.Fatalf("token 1 should not be in a series")
+	}
+}
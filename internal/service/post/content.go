@@ -0,0 +1,70 @@
+package post
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"in-server/pkg/apperr"
+	awss3 "in-server/pkg/aws/s3"
+	"in-server/pkg/markdown"
+)
+
+func renderedKey(liveKey string) string {
+	return strings.TrimSuffix(liveKey, ".json") + ".content.json"
+}
+
+// saveRenderedContent renders the Content attribute once at publish time and
+// stores the sanitized HTML and TOC next to the metadata object.
+func (s *Service) saveRenderedContent(ctx context.Context, liveKey string, payload NftMetadata) error {
+	content := attrValue(payload.Attributes, "Content")
+	if content == "" {
+		return nil
+	}
+
+	rendered, err := markdown.Render(content)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("marshal rendered content: %w", err)
+	}
+	if _, err := awss3.PutObject(ctx, s.cfg, renderedKey(liveKey), bytes.NewReader(data), "application/json", ""); err != nil {
+		return apperr.Wrap(err, apperr.Post.ErrUploadMetadata.Code, "save rendered content", apperr.Post.ErrUploadMetadata.Status)
+	}
+	return nil
+}
+
+// WithRenderedContent fills ContentHTML and TOC from the stored render,
+// rendering on the fly for posts published before renders were stored.
+func (s *Service) WithRenderedContent(ctx context.Context, p Post) (Post, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if strings.TrimSpace(p.Content) == "" {
+		return p, nil
+	}
+
+	var rendered markdown.Result
+	raw, err := s.readObject(ctx, renderedKey(extractKeyFromMetadataURL(p.MetadataURL)))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &rendered); err != nil {
+			return Post{}, fmt.Errorf("decode rendered content: %w", err)
+		}
+	case errors.Is(err, errObjectNotFound):
+		if rendered, err = markdown.Render(p.Content); err != nil {
+			return Post{}, err
+		}
+	default:
+		return Post{}, err
+	}
+
+	p.ContentHTML = rendered.HTML
+	p.TOC = rendered.TOC
+	return p, nil
+}
@@ -0,0 +1,114 @@
+package post
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"in-server/pkg/apperr"
+	awss3 "in-server/pkg/aws/s3"
+	pkgtypes "in-server/pkg/types"
+)
+
+type MetadataAction string
+
+const (
+	MetadataKeep    MetadataAction = "keep"
+	MetadataDelete  MetadataAction = "delete"
+	MetadataArchive MetadataAction = "archive"
+)
+
+type DeleteResult struct {
+	TokenID     string         `json:"tokenId"`
+	MetadataURL string         `json:"metadataUrl,omitempty"`
+	Metadata    MetadataAction `json:"metadata"`
+	ArchivedURL string         `json:"archivedUrl,omitempty"`
+}
+
+// Delete burns the post token through the forwarder and then applies action
+// to its S3 metadata object. Metadata cleanup failures are logged and reported
+// as MetadataKeep since the burn has already happened on-chain.
+func (s *Service) Delete(ctx context.Context, adminCode, ref string, action MetadataAction) (DeleteResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.eth == nil {
+		return DeleteResult{}, fmt.Errorf("eth client is nil")
+	}
+	if s.fb == nil {
+		return DeleteResult{}, fmt.Errorf("firebase client is nil")
+	}
+
+	adminCode = strings.TrimSpace(adminCode)
+	if adminCode == "" {
+		return DeleteResult{}, apperr.Post.ErrAdminCodeMissing
+	}
+
+	switch action {
+	case "":
+		action = MetadataKeep
+	case MetadataKeep, MetadataDelete, MetadataArchive:
+	default:
+		return DeleteResult{}, apperr.Post.ErrInvalidRequest
+	}
+
+	pk, ownerAddr, err := s.eth.Wallet(adminCode)
+	if err != nil {
+		return DeleteResult{}, apperr.Wrap(err, apperr.Post.ErrInvalidAdminCode.Code, apperr.Post.ErrInvalidAdminCode.Message, apperr.Post.ErrInvalidAdminCode.Status)
+	}
+
+	p, tokenID, err := s.resolveOwned(ctx, ownerAddr, ref)
+	if err != nil {
+		return DeleteResult{}, err
+	}
+
+	receipt, err := s.eth.ExecuteMetaTx(ctx, s.fb, pkgtypes.POSTSTORAGE, pk, "burn", tokenID)
+	if err != nil {
+		return DeleteResult{}, apperr.Wrap(err, apperr.Post.ErrDeleteFailed.Code, apperr.Post.ErrDeleteFailed.Message, apperr.Post.ErrDeleteFailed.Status)
+	}
+	if receipt == nil || receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return DeleteResult{}, apperr.Wrap(fmt.Errorf("meta tx status %v", receiptStatus(receipt)), apperr.Post.ErrDeleteFailed.Code, apperr.Post.ErrDeleteFailed.Message, apperr.Post.ErrDeleteFailed.Status)
+	}
+
+	s.cache.invalidate(ownerAddr, p.MetadataURL)
+
+	result := DeleteResult{
+		TokenID:     tokenID.String(),
+		MetadataURL: p.MetadataURL,
+		Metadata:    MetadataKeep,
+	}
+	if action == MetadataKeep {
+		return result, nil
+	}
+
+	key := extractKeyFromMetadataURL(p.MetadataURL)
+	addressPrefix := fmt.Sprintf("users/%s/", ownerAddr.Hex())
+	if key == "" || !strings.HasPrefix(key, addressPrefix) {
+		log.Printf("post delete: skip metadata %s for token %s: outside %s", p.MetadataURL, result.TokenID, addressPrefix)
+		return result, nil
+	}
+
+	if action == MetadataArchive {
+		archivedURL, err := awss3.CopyObject(ctx, s.cfg, key, archiveKey(addressPrefix, key), "")
+		if err != nil {
+			log.Printf("post delete: archive metadata %s: %v", key, err)
+			return result, nil
+		}
+		result.ArchivedURL = archivedURL
+	}
+
+	if err := awss3.DeleteObject(ctx, s.cfg, key, ""); err != nil {
+		log.Printf("post delete: delete metadata %s: %v", key, err)
+		return result, nil
+	}
+	result.Metadata = action
+
+	return result, nil
+}
+
+func archiveKey(addressPrefix, key string) string {
+	return addressPrefix + "archive/" + strings.TrimPrefix(key, addressPrefix)
+}
@@ -0,0 +1,248 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/types"
+)
+
+// Get resolves a single post by token ID or slug. Numeric references are
+// tried as token IDs first and fall back to a slug lookup.
+func (s *Service) Get(ctx context.Context, ref string) (Post, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	p, err := s.get(ctx, ref)
+	if err != nil {
+		return Post{}, err
+	}
+	posts := []Post{p}
+	if common.IsHexAddress(p.Owner) {
+		s.attachAuthor(ctx, common.HexToAddress(p.Owner), posts)
+	}
+	s.attachReactions(ctx, posts)
+	s.attachViews(ctx, posts)
+	s.attachSeries(ctx, posts)
+	return posts[0], nil
+}
+
+func (s *Service) get(ctx context.Context, ref string) (Post, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return Post{}, apperr.Post.ErrInvalidRequest
+	}
+
+	if tokenID, ok := parseTokenID(ref); ok {
+		p, err := s.getByTokenID(ctx, tokenID)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, apperr.Post.ErrPostNotFound) {
+			return Post{}, err
+		}
+	}
+
+	// Slugs are only unique per wallet; the server admin wallet wins.
+	owners, err := s.knownOwners(ctx)
+	if err != nil {
+		return Post{}, err
+	}
+	for _, ownerAddr := range owners {
+		p, err := s.getBySlug(ctx, ownerAddr, ref)
+		if !errors.Is(err, apperr.Post.ErrPostNotFound) {
+			return p, err
+		}
+	}
+	return Post{}, apperr.Post.ErrPostNotFound
+}
+
+func (s *Service) getByTokenID(ctx context.Context, tokenID *big.Int) (Post, error) {
+	if s.eth == nil {
+		return Post{}, fmt.Errorf("eth client is nil")
+	}
+
+	contract, _, err := s.eth.Contract(types.POSTSTORAGE)
+	if err != nil {
+		return Post{}, fmt.Errorf("bind post storage: %w", err)
+	}
+
+	var uri string
+	out := []any{&uri}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "postURI", tokenID); err != nil {
+		if isExecutionReverted(err) {
+			return Post{}, apperr.Post.ErrPostNotFound
+		}
+		return Post{}, fmt.Errorf("call postURI: %w", err)
+	}
+	if strings.TrimSpace(uri) == "" {
+		return Post{}, apperr.Post.ErrPostNotFound
+	}
+	uri = s.aliasedURI(ctx, tokenID, uri)
+
+	holder, err := s.tokenOwner(ctx, tokenID)
+	if err != nil {
+		return Post{}, err
+	}
+
+	meta, err := s.fetchMetadata(ctx, uri)
+	if err != nil {
+		return Post{}, fmt.Errorf("fetch metadata for %s: %w", uri, err)
+	}
+	p := mapMetadataToPost(meta, tokenID.String(), uri)
+	p.Owner = holder.Hex()
+	return p, nil
+}
+
+func (s *Service) getBySlug(ctx context.Context, ownerAddr common.Address, slug string) (Post, error) {
+	target := toPathSegment(slug)
+	if target == "" {
+		return Post{}, apperr.Post.ErrPostNotFound
+	}
+
+	refs, err := s.postRefs(ctx, ownerAddr)
+	if err != nil {
+		return Post{}, err
+	}
+
+	// Stop fetching remaining metadata as soon as one post matches.
+	searchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		found *Post
+	)
+	eg, egctx := errgroup.WithContext(searchCtx)
+	for _, ref := range refs {
+		ref := ref
+		eg.Go(func() error {
+			meta, err := s.fetchMetadata(egctx, ref.Uri)
+			if err != nil {
+				if egctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("fetch metadata for %s: %w", ref.Uri, err)
+			}
+			p := mapMetadataToPost(meta, ref.Id.String(), ref.Uri)
+			p.Owner = ownerAddr.Hex()
+			if toPathSegment(p.Slug) != target {
+				return nil
+			}
+			mu.Lock()
+			if found == nil {
+				found = &p
+			}
+			mu.Unlock()
+			cancel()
+			return nil
+		})
+	}
+	waitErr := eg.Wait()
+
+	if found != nil {
+		return *found, nil
+	}
+	if waitErr != nil {
+		return Post{}, waitErr
+	}
+	return Post{}, apperr.Post.ErrPostNotFound
+}
+
+// resolveOwned resolves ref like Get but scoped to ownerAddr, and verifies the
+// token is still held by that wallet.
+func (s *Service) resolveOwned(ctx context.Context, ownerAddr common.Address, ref string) (Post, *big.Int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return Post{}, nil, apperr.Post.ErrInvalidRequest
+	}
+
+	var (
+		p   Post
+		err error
+	)
+	tokenID, numeric := parseTokenID(ref)
+	if numeric {
+		p, err = s.getByTokenID(ctx, tokenID)
+	}
+	if !numeric || errors.Is(err, apperr.Post.ErrPostNotFound) {
+		p, err = s.getBySlug(ctx, ownerAddr, ref)
+	}
+	if err != nil {
+		return Post{}, nil, err
+	}
+
+	tokenID, ok := parseTokenID(p.TokenID)
+	if !ok {
+		return Post{}, nil, fmt.Errorf("invalid token id %q", p.TokenID)
+	}
+
+	// Both lookups set Owner from the chain: ownerOf for token IDs, and the
+	// getPosts listing of ownerAddr for slugs.
+	if !common.IsHexAddress(p.Owner) || common.HexToAddress(p.Owner) != ownerAddr {
+		return Post{}, nil, apperr.Post.ErrNotPostOwner
+	}
+	return p, tokenID, nil
+}
+
+// Exists reports apperr.Post.ErrPostNotFound unless tokenID is a minted post.
+// It only asks the chain for the holder and skips loading metadata.
+func (s *Service) Exists(ctx context.Context, tokenID string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	id, ok := parseTokenID(strings.TrimSpace(tokenID))
+	if !ok {
+		return apperr.Post.ErrPostNotFound
+	}
+	_, err := s.tokenOwner(ctx, id)
+	return err
+}
+
+func (s *Service) tokenOwner(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+	if s.eth == nil {
+		return common.Address{}, fmt.Errorf("eth client is nil")
+	}
+
+	contract, _, err := s.eth.Contract(types.POSTSTORAGE)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("bind post storage: %w", err)
+	}
+
+	var holder common.Address
+	out := []any{&holder}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "ownerOf", tokenID); err != nil {
+		if isExecutionReverted(err) {
+			return common.Address{}, apperr.Post.ErrPostNotFound
+		}
+		return common.Address{}, fmt.Errorf("call ownerOf: %w", err)
+	}
+	return holder, nil
+}
+
+func parseTokenID(ref string) (*big.Int, bool) {
+	for _, r := range ref {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+	}
+	n, ok := new(big.Int).SetString(ref, 10)
+	return n, ok
+}
+
+func isExecutionReverted(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "execution reverted")
+}
@@ -0,0 +1,277 @@
+package post
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
+
+	"in-server/pkg/apperr"
+	awss3 "in-server/pkg/aws/s3"
+)
+
+type Draft struct {
+	ID          string      `json:"id"`
+	Owner       string      `json:"owner"`
+	Payload     NftMetadata `json:"payload"`
+	MetadataURL string      `json:"metadataUrl,omitempty"`
+	CreatedAt   string      `json:"createdAt"`
+	UpdatedAt   string      `json:"updatedAt"`
+}
+
+// Create stores payload as a new draft for the admin wallet. metadataURL is
+// kept so that promoting the draft updates an existing post instead of
+// minting a new one.
+func (s *Service) Create(ctx context.Context, adminCode string, payload NftMetadata, metadataURL string) (Draft, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return Draft{}, err
+	}
+
+	id, err := newDraftID()
+	if err != nil {
+		return Draft{}, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	draft := Draft{
+		ID:          id,
+		Owner:       ownerAddr.Hex(),
+		Payload:     payload,
+		MetadataURL: strings.TrimSpace(metadataURL),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.putDraft(ctx, ownerAddr, draft); err != nil {
+		return Draft{}, err
+	}
+	return draft, nil
+}
+
+func (s *Service) ListDrafts(ctx context.Context, adminCode string) ([]Draft, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := awss3.ListObjects(ctx, s.cfg, s.draftPrefix(ownerAddr), "")
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.Post.ErrDraftStorage.Code, "list drafts", apperr.Post.ErrDraftStorage.Status)
+	}
+
+	keys := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		if obj.Key != nil && strings.HasSuffix(*obj.Key, ".json") {
+			keys = append(keys, *obj.Key)
+		}
+	}
+
+	drafts := make([]Draft, len(keys))
+	eg, egctx := errgroup.WithContext(ctx)
+	for i, key := range keys {
+		i, key := i, key
+		eg.Go(func() error {
+			d, err := s.readDraft(egctx, key)
+			if err != nil {
+				return err
+			}
+			drafts[i] = d
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return parsePublishedAt(drafts[i].UpdatedAt).After(parsePublishedAt(drafts[j].UpdatedAt))
+	})
+	return drafts, nil
+}
+
+func (s *Service) GetDraft(ctx context.Context, adminCode, id string) (Draft, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return Draft{}, err
+	}
+	key, err := s.draftKey(ownerAddr, id)
+	if err != nil {
+		return Draft{}, err
+	}
+	return s.readDraft(ctx, key)
+}
+
+func (s *Service) UpdateDraft(ctx context.Context, adminCode, id string, payload NftMetadata, metadataURL *string) (Draft, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return Draft{}, err
+	}
+	key, err := s.draftKey(ownerAddr, id)
+	if err != nil {
+		return Draft{}, err
+	}
+
+	draft, err := s.readDraft(ctx, key)
+	if err != nil {
+		return Draft{}, err
+	}
+	draft.Payload = payload
+	if metadataURL != nil {
+		draft.MetadataURL = strings.TrimSpace(*metadataURL)
+	}
+	draft.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if err := s.putDraft(ctx, ownerAddr, draft); err != nil {
+		return Draft{}, err
+	}
+	return draft, nil
+}
+
+func (s *Service) DeleteDraft(ctx context.Context, adminCode, id string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return err
+	}
+	key, err := s.draftKey(ownerAddr, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.readDraft(ctx, key); err != nil {
+		return err
+	}
+	if err := awss3.DeleteObject(ctx, s.cfg, key, ""); err != nil {
+		return apperr.Wrap(err, apperr.Post.ErrDraftStorage.Code, "delete draft", apperr.Post.ErrDraftStorage.Status)
+	}
+	return nil
+}
+
+// PromoteDraft publishes the draft through Publish and removes it once the
+// post has been saved.
+func (s *Service) PromoteDraft(ctx context.Context, adminCode, id string) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	draft, err := s.GetDraft(ctx, adminCode, id)
+	if err != nil {
+		return "", err
+	}
+
+	metadataURL, err := s.Publish(ctx, adminCode, draft.Payload, draft.MetadataURL)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.DeleteDraft(ctx, adminCode, id); err != nil {
+		log.Printf("promote draft: remove draft %s: %v", id, err)
+	}
+	return metadataURL, nil
+}
+
+// AuthorizeWallet checks that adminCode unlocks a wallet, for handlers that
+// must reject a request before the service call that would otherwise do so.
+func (s *Service) AuthorizeWallet(adminCode string) error {
+	_, err := s.adminWallet(adminCode)
+	return err
+}
+
+func (s *Service) adminWallet(adminCode string) (common.Address, error) {
+	if s.eth == nil {
+		return common.Address{}, fmt.Errorf("eth client is nil")
+	}
+	adminCode = strings.TrimSpace(adminCode)
+	if adminCode == "" {
+		return common.Address{}, apperr.Post.ErrAdminCodeMissing
+	}
+	_, ownerAddr, err := s.eth.Wallet(adminCode)
+	if err != nil {
+		return common.Address{}, apperr.Wrap(err, apperr.Post.ErrInvalidAdminCode.Code, apperr.Post.ErrInvalidAdminCode.Message, apperr.Post.ErrInvalidAdminCode.Status)
+	}
+	return ownerAddr, nil
+}
+
+func (s *Service) draftPrefix(ownerAddr common.Address) string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return fmt.Sprintf("users/%s/drafts/%s/", ownerAddr.Hex(), env)
+}
+
+func (s *Service) draftKey(ownerAddr common.Address, id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || id != path.Base(id) || toPathSegment(id) != id {
+		return "", apperr.Post.ErrDraftNotFound
+	}
+	return s.draftPrefix(ownerAddr) + id + ".json", nil
+}
+
+func (s *Service) putDraft(ctx context.Context, ownerAddr common.Address, draft Draft) error {
+	key, err := s.draftKey(ownerAddr, draft.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return fmt.Errorf("marshal draft: %w", err)
+	}
+	if _, err := awss3.PutObject(ctx, s.cfg, key, bytes.NewReader(data), "application/json", ""); err != nil {
+		return apperr.Wrap(err, apperr.Post.ErrDraftStorage.Code, "save draft", apperr.Post.ErrDraftStorage.Status)
+	}
+	return nil
+}
+
+func (s *Service) readDraft(ctx context.Context, key string) (Draft, error) {
+	raw, err := s.readObject(ctx, key)
+	if errors.Is(err, errObjectNotFound) {
+		return Draft{}, apperr.Post.ErrDraftNotFound
+	}
+	if err != nil {
+		return Draft{}, apperr.Wrap(err, apperr.Post.ErrDraftStorage.Code, "read draft", apperr.Post.ErrDraftStorage.Status)
+	}
+
+	var draft Draft
+	if err := json.Unmarshal(raw, &draft); err != nil {
+		return Draft{}, fmt.Errorf("decode draft %s: %w", key, err)
+	}
+	return draft, nil
+}
+
+func newDraftID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate draft id: %w", err)
+	}
+	return fmt.Sprintf("draft-%s-%s", strings.ToLower(timestampForKey()), hex.EncodeToString(buf)), nil
+}
@@ -0,0 +1,208 @@
+package post
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"in-server/pkg/markdown"
+)
+
+const (
+	// Korean readers average about 500 characters a minute, English readers
+	// about 230 words.
+	koreanCharsPerMinute  = 500
+	englishWordsPerMinute = 230
+
+	summaryMaxRunes = 160
+)
+
+// derivedTraits are the attributes enrichMetadata can compute.
+var derivedTraits = []string{"ReadingTimeMinutes", "Summary", "StructuredData"}
+
+// enrichMetadata fills ReadingTimeMinutes, Summary and StructuredData from the
+// rest of the metadata and records them in Generated. Values listed there from
+// an earlier publish are recomputed; values the author wrote by hand, which
+// are absent from Generated, are kept as they are.
+func (s *Service) enrichMetadata(payload NftMetadata) NftMetadata {
+	out := payload
+	out.Attributes = append([]NftAttribute(nil), payload.Attributes...)
+	out.Generated = nil
+
+	previous := make(map[string]bool, len(payload.Generated))
+	for _, trait := range payload.Generated {
+		previous[strings.TrimSpace(trait)] = true
+	}
+	for trait := range previous {
+		out.Attributes = removeAttr(out.Attributes, trait)
+	}
+
+	content := attrValue(out.Attributes, "Content")
+	plain := markdown.PlainText(content)
+
+	minutes := attrInt(attrRaw(out.Attributes, "ReadingTimeMinutes"))
+	if minutes <= 0 && plain != "" {
+		minutes = readingTimeMinutes(plain)
+		out.Attributes = setAttr(out.Attributes, NftAttribute{
+			TraitType:   "ReadingTimeMinutes",
+			Value:       json.RawMessage(strconv.Itoa(minutes)),
+			DisplayType: "number",
+		})
+		out.Generated = append(out.Generated, "ReadingTimeMinutes")
+	}
+
+	summary := attrValue(out.Attributes, "Summary")
+	if summary == "" {
+		summary = deriveSummary(content, out.Description)
+		if summary != "" {
+			out.Attributes = setAttr(out.Attributes, NftAttribute{
+				TraitType: "Summary",
+				Value:     json.RawMessage(mustJSONQuote(summary)),
+			})
+			out.Generated = append(out.Generated, "Summary")
+		}
+	}
+
+	if attrValue(out.Attributes, "StructuredData") == "" {
+		ld := s.blogPosting(out, summary, plain, minutes)
+		out.Attributes = setAttr(out.Attributes, NftAttribute{
+			TraitType: "StructuredData",
+			Value:     json.RawMessage(mustJSONQuote(ld)),
+		})
+		out.Generated = append(out.Generated, "StructuredData")
+	}
+
+	return out
+}
+
+// readingTimeMinutes counts Hangul syllables and the remaining words
+// separately so mixed Korean/English posts get a sensible estimate.
+func readingTimeMinutes(plain string) int {
+	hangul := 0
+	var rest strings.Builder
+	for _, r := range plain {
+		if unicode.Is(unicode.Hangul, r) {
+			hangul++
+			rest.WriteRune(' ')
+			continue
+		}
+		rest.WriteRune(r)
+	}
+	words := len(strings.Fields(rest.String()))
+
+	minutes := float64(hangul)/koreanCharsPerMinute + float64(words)/englishWordsPerMinute
+	if minutes < 1 {
+		return 1
+	}
+	return int(math.Ceil(minutes))
+}
+
+func deriveSummary(content, description string) string {
+	text := markdown.FirstParagraph(content)
+	if text == "" {
+		text = description
+	}
+	return truncateRunes(strings.Join(strings.Fields(text), " "), summaryMaxRunes)
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	cut := strings.TrimRightFunc(string(runes[:max]), func(r rune) bool {
+		return !unicode.IsSpace(r)
+	})
+	if cut == "" {
+		cut = string(runes[:max])
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace) + "…"
+}
+
+// blogPosting builds a schema.org BlogPosting JSON-LD document.
+func (s *Service) blogPosting(payload NftMetadata, summary, plain string, minutes int) string {
+	doc := map[string]any{
+		"@context": "https://schema.org",
+		"@type":    "BlogPosting",
+		"headline": strings.TrimSpace(payload.Name),
+	}
+	if summary != "" {
+		doc["description"] = summary
+	} else if d := strings.TrimSpace(payload.Description); d != "" {
+		doc["description"] = d
+	}
+	if u := strings.TrimSpace(payload.ExternalURL); u != "" {
+		doc["url"] = u
+		doc["mainEntityOfPage"] = map[string]any{"@type": "WebPage", "@id": u}
+	}
+	if img := strings.TrimSpace(payload.Image); img != "" {
+		doc["image"] = img
+	}
+	if published := attrValue(payload.Attributes, "PublishedAt"); published != "" {
+		doc["datePublished"] = published
+	}
+	doc["dateModified"] = time.Now().UTC().Format(time.RFC3339)
+	if tags := strings.Fields(attrValue(payload.Attributes, "Tags")); len(tags) > 0 {
+		doc["keywords"] = strings.Join(tags, ", ")
+	}
+	if lab := attrValue(payload.Attributes, "Lab"); lab != "" {
+		doc["articleSection"] = lab
+		doc["author"] = map[string]any{"@type": "Organization", "name": lab}
+	}
+	if site := strings.TrimSpace(s.cfg.Site.Title); site != "" {
+		publisher := map[string]any{"@type": "Organization", "name": site}
+		if u := strings.TrimSpace(s.cfg.Site.URL); u != "" {
+			publisher["url"] = u
+		}
+		doc["publisher"] = publisher
+	}
+	if lang := strings.TrimSpace(s.cfg.Site.Language); lang != "" {
+		doc["inLanguage"] = lang
+	}
+	if plain != "" {
+		doc["wordCount"] = len(strings.Fields(plain))
+	}
+	if minutes > 0 {
+		doc["timeRequired"] = fmt.Sprintf("PT%dM", minutes)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func attrRaw(attrs []NftAttribute, trait string) json.RawMessage {
+	for _, a := range attrs {
+		if strings.TrimSpace(a.TraitType) == trait {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func removeAttr(attrs []NftAttribute, trait string) []NftAttribute {
+	out := attrs[:0]
+	for _, a := range attrs {
+		if strings.TrimSpace(a.TraitType) != trait {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// setAttr replaces the first attribute with the same trait type, or appends.
+func setAttr(attrs []NftAttribute, attr NftAttribute) []NftAttribute {
+	for i, a := range attrs {
+		if strings.TrimSpace(a.TraitType) == attr.TraitType {
+			attrs[i] = attr
+			return attrs
+		}
+	}
+	return append(attrs, attr)
+}
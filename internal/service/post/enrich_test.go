@@ -0,0 +1,101 @@
+package post
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReadingTimeMixesKoreanAndEnglish(t *testing.T) {
+	korean := strings.Repeat("가", 1000)
+	english := strings.Repeat("word ", 460)
+
+	if got := readingTimeMinutes(korean); got != 2 {
+		t.Fatalf("korean: got %d want 2", got)
+	}
+	if got := readingTimeMinutes(english); got != 2 {
+		t.Fatalf("english: got %d want 2", got)
+	}
+	if got := readingTimeMinutes(korean + " " + english); got != 4 {
+		t.Fatalf("mixed: got %d want 4", got)
+	}
+	if got := readingTimeMinutes("짧은 글"); got != 1 {
+		t.Fatalf("short: got %d want 1", got)
+	}
+}
+
+func TestEnrichMetadataKeepsAuthorValues(t *testing.T) {
+	s := &Service{}
+	payload := NftMetadata{
+		Name:        "Hello",
+		Description: "desc",
+		ExternalURL: "https://in.example/dev-lab/hello",
+		Attributes: []NftAttribute{
+			{TraitType: "Content", Value: json.RawMessage(mustJSONQuote("# Title\n\nFirst **paragraph** here.\n\nSecond."))},
+			{TraitType: "Summary", Value: json.RawMessage(mustJSONQuote("hand written"))},
+			{TraitType: "PublishedAt", Value: json.RawMessage(mustJSONQuote("2024-03-01T00:00:00Z"))},
+		},
+	}
+
+	out := s.enrichMetadata(payload)
+	if got := attrValue(out.Attributes, "Summary"); got != "hand written" {
+		t.Fatalf("summary overwritten: %q", got)
+	}
+	if got := attrInt(attrRaw(out.Attributes, "ReadingTimeMinutes")); got != 1 {
+		t.Fatalf("reading time: %d", got)
+	}
+
+	var ld map[string]any
+	if err := json.Unmarshal([]byte(attrValue(out.Attributes, "StructuredData")), &ld); err != nil {
+		t.Fatalf("structured data: %v", err)
+	}
+	if ld["@type"] != "BlogPosting" || ld["headline"] != "Hello" || ld["datePublished"] != "2024-03-01T00:00:00Z" {
+		t.Fatalf("unexpected json-ld: %v", ld)
+	}
+	if len(payload.Attributes) != 3 {
+		t.Fatalf("input attributes mutated")
+	}
+
+	if got := deriveSummary("# Title\n\nFirst **paragraph** here.", "desc"); got != "First paragraph here." {
+		t.Fatalf("derived summary: %q", got)
+	}
+}
+
+func TestEnrichMetadataRecomputesGenerated(t *testing.T) {
+	s := &Service{}
+	payload := NftMetadata{
+		Name:        "Hello",
+		Description: "desc",
+		Attributes: []NftAttribute{
+			{TraitType: "Content", Value: json.RawMessage(mustJSONQuote("Short first version."))},
+		},
+	}
+	first := s.enrichMetadata(payload)
+	if len(first.Generated) != 3 {
+		t.Fatalf("generated = %v", first.Generated)
+	}
+
+	edited := first
+	edited.Attributes = setAttr(append([]NftAttribute(nil), first.Attributes...), NftAttribute{
+		TraitType: "Content",
+		Value:     json.RawMessage(mustJSONQuote("Rewritten opening. " + strings.Repeat("word ", 500))),
+	})
+	second := s.enrichMetadata(edited)
+	if got := attrInt(attrRaw(second.Attributes, "ReadingTimeMinutes")); got != 3 {
+		t.Fatalf("reading time not recomputed: %d", got)
+	}
+	if got := attrValue(second.Attributes, "Summary"); !strings.HasPrefix(got, "Rewritten opening.") {
+		t.Fatalf("summary not recomputed: %q", got)
+	}
+
+	// Dropping a trait from Generated marks it as the editor's own value.
+	edited.Generated = []string{"ReadingTimeMinutes", "StructuredData"}
+	edited.Attributes = setAttr(edited.Attributes, NftAttribute{TraitType: "Summary", Value: json.RawMessage(mustJSONQuote("hand written"))})
+	third := s.enrichMetadata(edited)
+	if got := attrValue(third.Attributes, "Summary"); got != "hand written" {
+		t.Fatalf("override lost: %q", got)
+	}
+	if len(third.Generated) != 2 {
+		t.Fatalf("generated = %v", third.Generated)
+	}
+}
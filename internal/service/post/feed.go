@@ -0,0 +1,324 @@
+package post
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const feedItemLimit = 50
+
+type Feed struct {
+	Title       string
+	Description string
+	Language    string
+	SiteURL     string
+	SelfURL     string
+	Updated     time.Time
+	Items       []Post
+}
+
+// Feed collects the latest posts, optionally scoped to a lab segment, for
+// syndication output.
+func (s *Service) Feed(ctx context.Context, lab, selfURL string) (Feed, error) {
+	page, err := s.Query(ctx, ListQuery{Lab: lab, Limit: feedItemLimit})
+	if err != nil {
+		return Feed{}, err
+	}
+
+	title := strings.TrimSpace(s.cfg.Site.Title)
+	if title == "" {
+		title = "IN Labs"
+	}
+	if lab = toPathSegment(lab); lab != "" {
+		labTitle := lab
+		if len(page.Items) > 0 && page.Items[0].LabName != "" {
+			labTitle = page.Items[0].LabName
+		}
+		title = fmt.Sprintf("%s - %s", title, labTitle)
+	}
+
+	siteURL := s.siteURL(page.Items)
+
+	var updated time.Time
+	for _, p := range page.Items {
+		if t := parsePublishedAt(p.PublishedAt); t.After(updated) {
+			updated = t
+		}
+	}
+
+	return Feed{
+		Title:       title,
+		Description: strings.TrimSpace(s.cfg.Site.Description),
+		Language:    strings.TrimSpace(s.cfg.Site.Language),
+		SiteURL:     siteURL,
+		SelfURL:     selfURL,
+		Updated:     updated,
+		Items:       page.Items,
+	}, nil
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string       `xml:"title"`
+	Link          string       `xml:"link"`
+	Description   string       `xml:"description"`
+	Language      string       `xml:"language,omitempty"`
+	LastBuildDate string       `xml:"lastBuildDate,omitempty"`
+	AtomLink      *rssAtomLink `xml:"atom:link,omitempty"`
+	Items         []rssItem    `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description string        `xml:"description,omitempty"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// RSS renders the feed as an RSS 2.0 document.
+func (f Feed) RSS() ([]byte, error) {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.SiteURL,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       make([]rssItem, 0, len(f.Items)),
+	}
+	if !f.Updated.IsZero() {
+		channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	if f.SelfURL != "" {
+		channel.AtomLink = &rssAtomLink{Href: f.SelfURL, Rel: "self", Type: "application/rss+xml"}
+	}
+
+	for _, p := range f.Items {
+		link := f.postLink(p)
+		item := rssItem{
+			Title:       p.Title,
+			Link:        link,
+			GUID:        rssGUID{Value: link, IsPermaLink: true},
+			Description: feedSummary(p),
+			Categories:  p.Tags,
+		}
+		if t := parsePublishedAt(p.PublishedAt); !t.IsZero() {
+			item.PubDate = t.UTC().Format(time.RFC1123Z)
+		}
+		if p.Image != "" {
+			item.Enclosure = &rssEnclosure{URL: p.Image, Type: imageType(p.Image)}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	return marshalXML(rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	})
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Author   atomPerson  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom renders the feed as an Atom 1.0 document.
+func (f Feed) Atom() ([]byte, error) {
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	id := f.SelfURL
+	if id == "" {
+		id = f.SiteURL
+	}
+
+	doc := atomDocument{
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       id,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Author:   atomPerson{Name: f.Title},
+		Entries:  make([]atomEntry, 0, len(f.Items)),
+	}
+	if f.SiteURL != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.SiteURL, Rel: "alternate", Type: "text/html"})
+	}
+	if f.SelfURL != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.SelfURL, Rel: "self", Type: "application/atom+xml"})
+	}
+
+	for _, p := range f.Items {
+		link := f.postLink(p)
+		published := parsePublishedAt(p.PublishedAt)
+		entryUpdated := published
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+
+		entry := atomEntry{
+			Title:   p.Title,
+			ID:      link,
+			Updated: entryUpdated.UTC().Format(time.RFC3339),
+			Links:   []atomLink{{Href: link, Rel: "alternate", Type: "text/html"}},
+		}
+		if !published.IsZero() {
+			entry.Published = published.UTC().Format(time.RFC3339)
+		}
+		if summary := feedSummary(p); summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: summary}
+		}
+		if p.Image != "" {
+			entry.Links = append(entry.Links, atomLink{Href: p.Image, Rel: "enclosure", Type: imageType(p.Image)})
+		}
+		for _, tag := range p.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+func (f Feed) postLink(p Post) string {
+	return absolutePostURL(f.SiteURL, p)
+}
+
+// SiteURL returns the configured SITE_URL without a trailing slash, or "".
+func (s *Service) SiteURL() string {
+	return strings.TrimRight(strings.TrimSpace(s.cfg.Site.URL), "/")
+}
+
+// siteURL returns the configured site origin, falling back to the origin of
+// the first post with an absolute external URL.
+func (s *Service) siteURL(posts []Post) string {
+	if siteURL := s.SiteURL(); siteURL != "" {
+		return siteURL
+	}
+	for _, p := range posts {
+		if origin := originOf(p.ExternalURL); origin != "" {
+			return origin
+		}
+	}
+	return ""
+}
+
+func absolutePostURL(siteURL string, p Post) string {
+	if u, err := url.Parse(p.ExternalURL); err == nil && u.IsAbs() {
+		return p.ExternalURL
+	}
+	href := p.Href
+	if href == "" {
+		href = p.ExternalURL
+	}
+	if siteURL == "" {
+		return href
+	}
+	return siteURL + "/" + strings.TrimLeft(href, "/")
+}
+
+func feedSummary(p Post) string {
+	if p.Summary != "" {
+		return p.Summary
+	}
+	return p.Description
+}
+
+func imageType(rawURL string) string {
+	ext := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		ext = path.Ext(u.Path)
+	}
+	if t := mime.TypeByExtension(strings.ToLower(ext)); t != "" {
+		return t
+	}
+	return "image/*"
+}
+
+func originOf(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func marshalXML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("encode xml: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
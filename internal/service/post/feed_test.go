@@ -0,0 +1,42 @@
+package post
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedEscapesAndLinks(t *testing.T) {
+	feed := Feed{
+		Title:   "IN Labs",
+		SiteURL: "https://in.example",
+		SelfURL: "https://api.example/posts/feed.xml",
+		Updated: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Items: []Post{{
+			Title:       `Tom & Jerry <3`,
+			Summary:     `"quoted" & <b>bold</b>`,
+			Href:        "/dev-lab/tom-and-jerry",
+			PublishedAt: "2024-03-01T00:00:00Z",
+			Tags:        []string{"go", "rss"},
+			Image:       "https://cdn.example/cover.png",
+		}},
+	}
+
+	for name, render := range map[string]func() ([]byte, error){"rss": feed.RSS, "atom": feed.Atom} {
+		out, err := render()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		body := string(out)
+		if strings.Contains(body, "Tom & Jerry") || !strings.Contains(body, "Tom &amp; Jerry &lt;3") {
+			t.Fatalf("%s: title not escaped:\n%s", name, body)
+		}
+		if !strings.Contains(body, "https://in.example/dev-lab/tom-and-jerry") {
+			t.Fatalf("%s: missing absolute post link:\n%s", name, body)
+		}
+		if err := xml.Unmarshal(out, new(struct{})); err != nil {
+			t.Fatalf("%s: invalid xml: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package post
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"in-server/pkg/apperr"
+)
+
+type LinkKind string
+
+const (
+	LinkInternal LinkKind = "internal"
+	LinkExternal LinkKind = "external"
+)
+
+type RelatedLink struct {
+	URL     string   `json:"url"`
+	Title   string   `json:"title,omitempty"`
+	Kind    LinkKind `json:"kind"`
+	Lab     string   `json:"lab,omitempty"`
+	Slug    string   `json:"slug,omitempty"`
+	TokenID string   `json:"tokenId,omitempty"`
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// parseRelatedLinks accepts the shapes authors write RelatedLinks in: a JSON
+// array of URLs or {url, title} objects, that same array encoded as a string,
+// or a plain string of URLs separated by whitespace or commas.
+func parseRelatedLinks(raw json.RawMessage) ([]RelatedLink, error) {
+	raw = json.RawMessage(strings.TrimSpace(string(raw)))
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		s = strings.TrimSpace(s)
+		if strings.HasPrefix(s, "[") {
+			return parseRelatedLinks(json.RawMessage(s))
+		}
+		var links []RelatedLink
+		for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' || r == '\r' }) {
+			links = append(links, RelatedLink{URL: f})
+		}
+		return links, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("related links must be a string or an array")
+	}
+	links := make([]RelatedLink, 0, len(items))
+	for i, item := range items {
+		var link RelatedLink
+		if err := json.Unmarshal(item, &link.URL); err != nil {
+			if err := json.Unmarshal(item, &link); err != nil {
+				return nil, fmt.Errorf("related link %d: %w", i, err)
+			}
+		}
+		link.URL = strings.TrimSpace(link.URL)
+		link.Title = strings.Join(strings.Fields(link.Title), " ")
+		if link.URL == "" {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// classifyLink marks a link internal when it is a site-relative path, a bare
+// slug, or an absolute URL on one of siteHosts. Internal links get their lab
+// and slug from the path, matching the /<lab>/<slug> layout of ExternalURL.
+func classifyLink(link RelatedLink, siteHosts []string) (RelatedLink, error) {
+	raw := link.URL
+	if slugPattern.MatchString(raw) {
+		link.Kind = LinkInternal
+		link.Slug = raw
+		return link, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return link, fmt.Errorf("%q is not a valid url", raw)
+	}
+
+	switch {
+	case u.Scheme == "" && u.Host == "" && strings.HasPrefix(u.Path, "/"):
+		link.Kind = LinkInternal
+	case (u.Scheme == "http" || u.Scheme == "https") && u.Host != "":
+		link.Kind = LinkExternal
+		for _, h := range siteHosts {
+			if h != "" && strings.EqualFold(u.Hostname(), h) {
+				link.Kind = LinkInternal
+				break
+			}
+		}
+	default:
+		return link, fmt.Errorf("%q must be an http(s) url or a site path", raw)
+	}
+
+	if link.Kind == LinkInternal {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		link.Slug = toPathSegment(parts[len(parts)-1])
+		if len(parts) > 1 {
+			link.Lab = toPathSegment(parts[0])
+		}
+		if link.Slug == "" {
+			return link, fmt.Errorf("%q does not point to a post", raw)
+		}
+	}
+	return link, nil
+}
+
+// resolveRelatedLinks validates the RelatedLinks attribute against the owner's
+// posts and rewrites it as a structured JSON array. Internal links that do
+// not match an existing post are reported back instead of being published.
+func (s *Service) resolveRelatedLinks(payload NftMetadata, posts []Post) (NftMetadata, error) {
+	raw := attrRaw(payload.Attributes, "RelatedLinks")
+	if raw == nil {
+		return payload, nil
+	}
+
+	links, err := parseRelatedLinks(raw)
+	if err != nil {
+		return NftMetadata{}, apperr.Wrap(err, apperr.Post.ErrInvalidRelatedLinks.Code, err.Error(), apperr.Post.ErrInvalidRelatedLinks.Status)
+	}
+
+	hosts := []string{hostOf(s.cfg.Site.URL), hostOf(payload.ExternalURL)}
+	var broken []string
+	resolved := make([]RelatedLink, 0, len(links))
+	for _, link := range links {
+		link, err := classifyLink(link, hosts)
+		if err != nil {
+			broken = append(broken, err.Error())
+			continue
+		}
+		if link.Kind == LinkInternal {
+			target, ok := findBySlug(posts, link.Lab, link.Slug)
+			if !ok {
+				broken = append(broken, fmt.Sprintf("%q does not match an existing post", link.URL))
+				continue
+			}
+			link = internalLink(link, target)
+		}
+		resolved = append(resolved, link)
+	}
+	if len(broken) > 0 {
+		msg := "broken related links: " + strings.Join(broken, "; ")
+		return NftMetadata{}, apperr.Wrap(fmt.Errorf("%d broken", len(broken)), apperr.Post.ErrInvalidRelatedLinks.Code, msg, apperr.Post.ErrInvalidRelatedLinks.Status)
+	}
+
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return NftMetadata{}, fmt.Errorf("marshal related links: %w", err)
+	}
+	out := payload
+	out.Attributes = setAttr(append([]NftAttribute(nil), payload.Attributes...), NftAttribute{
+		TraitType: "RelatedLinks",
+		Value:     json.RawMessage(mustJSONQuote(string(data))),
+	})
+	return out, nil
+}
+
+// relatedLinksForPost parses stored links for display. Links published before
+// they were resolved are only classified, not checked.
+func relatedLinksForPost(raw json.RawMessage, externalURL string) []RelatedLink {
+	links, err := parseRelatedLinks(raw)
+	if err != nil {
+		return nil
+	}
+	hosts := []string{hostOf(externalURL)}
+	out := make([]RelatedLink, 0, len(links))
+	for _, link := range links {
+		if link.Kind == "" {
+			classified, err := classifyLink(link, hosts)
+			if err != nil {
+				continue
+			}
+			link = classified
+		}
+		out = append(out, link)
+	}
+	return out
+}
+
+func findBySlug(posts []Post, lab, slug string) (Post, bool) {
+	for _, p := range posts {
+		if p.Slug != slug && toPathSegment(p.Slug) != slug {
+			continue
+		}
+		if lab != "" && p.LabSegment != lab {
+			continue
+		}
+		return p, true
+	}
+	return Post{}, false
+}
+
+func internalLink(link RelatedLink, target Post) RelatedLink {
+	if target.ExternalURL != "" {
+		link.URL = target.ExternalURL
+	} else if target.Href != "" {
+		link.URL = target.Href
+	}
+	if link.Title == "" {
+		link.Title = target.Title
+	}
+	link.Lab = target.LabSegment
+	link.Slug = target.Slug
+	link.TokenID = target.TokenID
+	return link
+}
+
+func hostOf(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
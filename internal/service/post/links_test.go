@@ -0,0 +1,51 @@
+package post
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"in-server/pkg/apperr"
+)
+
+func TestResolveRelatedLinks(t *testing.T) {
+	s := &Service{}
+	s.cfg.Site.URL = "https://in.example"
+	posts := []Post{{
+		TokenID:     "7",
+		Slug:        "part-one",
+		Title:       "Part One",
+		LabSegment:  "dev-lab",
+		ExternalURL: "https://in.example/dev-lab/part-one",
+	}}
+
+	value := `[{"url":"/dev-lab/part-one"},"https://go.dev/doc",{"url":"https://in.example/dev-lab/part-one","title":"Custom"}]`
+	payload := NftMetadata{
+		ExternalURL: "https://in.example/dev-lab/part-two",
+		Attributes:  []NftAttribute{{TraitType: "RelatedLinks", Value: json.RawMessage(mustJSONQuote(value))}},
+	}
+
+	out, err := s.resolveRelatedLinks(payload, posts)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	links := relatedLinksForPost(attrRaw(out.Attributes, "RelatedLinks"), payload.ExternalURL)
+	if len(links) != 3 {
+		t.Fatalf("unexpected links: %+v", links)
+	}
+	if links[0].Kind != LinkInternal || links[0].TokenID != "7" || links[0].Title != "Part One" || links[0].URL != posts[0].ExternalURL {
+		t.Fatalf("internal link not resolved: %+v", links[0])
+	}
+	if links[1].Kind != LinkExternal || links[1].URL != "https://go.dev/doc" {
+		t.Fatalf("external link: %+v", links[1])
+	}
+	if links[2].Title != "Custom" || links[2].Kind != LinkInternal {
+		t.Fatalf("absolute internal link: %+v", links[2])
+	}
+
+	payload.Attributes[0].Value = json.RawMessage(mustJSONQuote("/dev-lab/missing ftp://x.example"))
+	_, err = s.resolveRelatedLinks(payload, posts)
+	if !errors.Is(err, apperr.Post.ErrInvalidRelatedLinks) {
+		t.Fatalf("expected broken links error, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"in-server/pkg/apperr"
+	pkgtypes "in-server/pkg/types"
+)
+
+const (
+	DefaultMigrateChunkSize = 20
+	MaxMigrateChunkSize     = 50
+)
+
+type MigrateStatus string
+
+const (
+	MigrateReady    MigrateStatus = "ready"
+	MigrateMigrated MigrateStatus = "migrated"
+	MigrateSkipped  MigrateStatus = "skipped"
+	MigrateFailed   MigrateStatus = "failed"
+)
+
+type MigrateItemResult struct {
+	Index       int           `json:"index"`
+	Lab         string        `json:"lab"`
+	Slug        string        `json:"slug"`
+	Status      MigrateStatus `json:"status"`
+	MetadataURL string        `json:"metadataUrl,omitempty"`
+	TxHash      string        `json:"txHash,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	// Fields holds the ValidateMetadata errors of an invalid item.
+	Fields []apperr.FieldError `json:"fields,omitempty"`
+}
+
+type MigrateReport struct {
+	DryRun   bool                `json:"dryRun"`
+	Items    []MigrateItemResult `json:"items"`
+	Ready    int                 `json:"ready,omitempty"`
+	Migrated int                 `json:"migrated"`
+	Skipped  int                 `json:"skipped"`
+	Failed   int                 `json:"failed"`
+}
+
+// postInput mirrors PostStorage.PostInput for the migrate call.
+type postInput struct {
+	Recipient common.Address
+	Uri       string
+}
+
+// Migrate imports legacy posts in bulk: each document is uploaded like a new
+// Publish and the uploaded URIs are minted chunkSize at a time through
+// PostStorage.migrate. Posts whose lab/slug already exists, on-chain or earlier
+// in the batch, are skipped. With dryRun nothing is uploaded or minted.
+func (s *Service) Migrate(ctx context.Context, adminCode string, items []NftMetadata, chunkSize int, dryRun bool) (MigrateReport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.eth == nil {
+		return MigrateReport{}, fmt.Errorf("eth client is nil")
+	}
+	if !dryRun && s.fb == nil {
+		return MigrateReport{}, fmt.Errorf("firebase client is nil")
+	}
+
+	adminCode = strings.TrimSpace(adminCode)
+	if adminCode == "" {
+		return MigrateReport{}, apperr.Post.ErrAdminCodeMissing
+	}
+	if len(items) == 0 {
+		return MigrateReport{}, apperr.Post.ErrInvalidBody
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultMigrateChunkSize
+	}
+	if chunkSize > MaxMigrateChunkSize {
+		chunkSize = MaxMigrateChunkSize
+	}
+
+	pk, ownerAddr, err := s.eth.Wallet(adminCode)
+	if err != nil {
+		return MigrateReport{}, apperr.Wrap(err, apperr.Post.ErrInvalidAdminCode.Code, apperr.Post.ErrInvalidAdminCode.Message, apperr.Post.ErrInvalidAdminCode.Status)
+	}
+
+	posts, err := s.loadByOwner(ctx, ownerAddr)
+	if err != nil {
+		return MigrateReport{}, err
+	}
+	seen := make(map[string]bool, len(posts)+len(items))
+	for _, p := range posts {
+		seen[p.LabSegment+"/"+p.Slug] = true
+	}
+
+	// Related links may point at posts elsewhere in the batch.
+	linkTargets := append([]Post(nil), posts...)
+	for _, payload := range items {
+		if meta, err := toMetadata(payload); err == nil {
+			linkTargets = append(linkTargets, mapMetadataToPost(meta, "", ""))
+		}
+	}
+
+	report := MigrateReport{DryRun: dryRun, Items: make([]MigrateItemResult, len(items))}
+	var pending []int
+	for i, payload := range items {
+		res := &report.Items[i]
+		res.Index = i
+		res.Lab, res.Slug = postSegments(payload)
+
+		if err := ValidateMetadata(payload); err != nil {
+			res.Status, res.Error = MigrateFailed, errorMessage(err)
+			var appErr *apperr.Error
+			if errors.As(err, &appErr) {
+				res.Fields = appErr.Fields
+			}
+			continue
+		}
+		key := res.Lab + "/" + res.Slug
+		if seen[key] {
+			res.Status, res.Error = MigrateSkipped, apperr.Post.ErrDuplicatePost.Message
+			continue
+		}
+		seen[key] = true
+
+		if dryRun {
+			if _, err := s.resolveRelatedLinks(normalizeMetadata(payload), linkTargets); err != nil {
+				res.Status, res.Error = MigrateFailed, errorMessage(err)
+				continue
+			}
+			res.Status = MigrateReady
+			continue
+		}
+
+		normalized, err := s.resolveRelatedLinks(s.enrichMetadata(normalizeMetadata(payload)), linkTargets)
+		if err != nil {
+			res.Status, res.Error = MigrateFailed, errorMessage(err)
+			continue
+		}
+		uploadedURL, _, err := s.saveMetadata(ctx, ownerAddr.Hex(), res.Lab, normalized, "", "")
+		if err != nil {
+			res.Status, res.Error = MigrateFailed, err.Error()
+			continue
+		}
+		res.MetadataURL = uploadedURL
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += chunkSize {
+		end := min(start+chunkSize, len(pending))
+		chunk := pending[start:end]
+
+		inputs := make([]postInput, len(chunk))
+		for j, idx := range chunk {
+			inputs[j] = postInput{Recipient: ownerAddr, Uri: report.Items[idx].MetadataURL}
+		}
+
+		receipt, err := s.eth.ExecuteMetaTx(ctx, s.fb, pkgtypes.POSTSTORAGE, pk, "migrate", inputs)
+		if err == nil && (receipt == nil || receipt.Status != gethtypes.ReceiptStatusSuccessful) {
+			err = fmt.Errorf("meta tx status %v", receiptStatus(receipt))
+		}
+		for _, idx := range chunk {
+			res := &report.Items[idx]
+			if err != nil {
+				// Nothing was minted, so the uploaded objects would be orphans.
+				s.discardMetadata(ctx, extractKeyFromMetadataURL(res.MetadataURL))
+				res.Status, res.Error, res.MetadataURL = MigrateFailed, err.Error(), ""
+				continue
+			}
+			res.Status = MigrateMigrated
+			res.TxHash = receipt.TxHash.Hex()
+		}
+	}
+
+	if len(pending) > 0 {
+		s.cache.invalidate(ownerAddr)
+		s.rememberPublisher(ctx, ownerAddr)
+	}
+
+	for _, res := range report.Items {
+		switch res.Status {
+		case MigrateReady:
+			report.Ready++
+		case MigrateMigrated:
+			report.Migrated++
+		case MigrateSkipped:
+			report.Skipped++
+		case MigrateFailed:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// errorMessage prefers the user-facing apperr message, which for related
+// links lists the broken entries, followed by any field errors.
+func errorMessage(err error) string {
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) {
+		return err.Error()
+	}
+	if len(appErr.Fields) == 0 {
+		return appErr.Message
+	}
+	parts := make([]string, len(appErr.Fields))
+	for i, f := range appErr.Fields {
+		parts[i] = f.Field + " " + f.Message
+	}
+	return appErr.Message + ": " + strings.Join(parts, "; ")
+}
+
+// postSegments returns the lab and slug path segments Publish derives for
+// duplicate checks and S3 keys.
+func postSegments(payload NftMetadata) (lab, slug string) {
+	lab = toPathSegment(attrValue(payload.Attributes, "Lab"))
+	if lab == "" {
+		lab = "lab"
+	}
+	slug = toPathSegment(attrValue(payload.Attributes, "Slug"))
+	if slug == "" {
+		slug = "post"
+	}
+	return lab, slug
+}
@@ -0,0 +1,39 @@
+package post
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/abis"
+	"in-server/pkg/types"
+)
+
+func TestMigrateInputsPackAgainstABI(t *testing.T) {
+	artifacts, err := abis.Get("development")
+	if err != nil {
+		t.Fatalf("load abis: %v", err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifacts[types.POSTSTORAGE].ABI)))
+	if err != nil {
+		t.Fatalf("parse abi: %v", err)
+	}
+
+	inputs := []postInput{
+		{Recipient: common.HexToAddress("0x1"), Uri: "https://cdn.example/a.json"},
+		{Recipient: common.HexToAddress("0x1"), Uri: "https://cdn.example/b.json"},
+	}
+	if _, err := parsed.Pack("migrate", inputs); err != nil {
+		t.Fatalf("pack migrate: %v", err)
+	}
+}
+
+func TestMigrateErrorMessageListsFields(t *testing.T) {
+	err := ValidateMetadata(NftMetadata{Name: "x", Description: "y", ExternalURL: "https://in.example/a"})
+	got := errorMessage(err)
+	if !strings.HasPrefix(got, "metadata failed validation: ") || !strings.Contains(got, "attributes.Slug is required") {
+		t.Fatalf("errorMessage = %q", got)
+	}
+}
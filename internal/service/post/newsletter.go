@@ -0,0 +1,448 @@
+package post
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/eth"
+	"in-server/pkg/firebase"
+	googlemail "in-server/pkg/google"
+	"in-server/pkg/types"
+)
+
+type NewsletterStatus string
+
+const (
+	NewsletterPending   NewsletterStatus = "pending"
+	NewsletterCompleted NewsletterStatus = "completed"
+
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+)
+
+type DeliveryStatus string
+
+type Delivery struct {
+	Email    string         `json:"email"`
+	Status   DeliveryStatus `json:"status"`
+	Attempts int            `json:"attempts"`
+	Error    string         `json:"error,omitempty"`
+	SentAt   string         `json:"sentAt,omitempty"`
+}
+
+// Newsletter is one announcement fanned out to every subscriber. Recipients
+// are keyed by a hash of the address, since Firebase keys cannot hold emails,
+// and each delivery is written as soon as it is attempted so a partial send
+// resumes where it stopped.
+type Newsletter struct {
+	ID          string              `json:"id"`
+	Owner       string              `json:"owner"`
+	MetadataURL string              `json:"metadataUrl"`
+	Title       string              `json:"title"`
+	Subject     string              `json:"subject"`
+	Body        string              `json:"body"`
+	Status      NewsletterStatus    `json:"status"`
+	Recipients  map[string]Delivery `json:"recipients,omitempty"`
+	CreatedAt   string              `json:"createdAt"`
+	UpdatedAt   string              `json:"updatedAt"`
+}
+
+type NewsletterSummary struct {
+	ID          string           `json:"id"`
+	MetadataURL string           `json:"metadataUrl"`
+	Title       string           `json:"title"`
+	Status      NewsletterStatus `json:"status"`
+	Total       int              `json:"total"`
+	Sent        int              `json:"sent"`
+	Failed      int              `json:"failed"`
+	Pending     int              `json:"pending"`
+	CreatedAt   string           `json:"createdAt"`
+	UpdatedAt   string           `json:"updatedAt"`
+}
+
+func (n Newsletter) summary() NewsletterSummary {
+	out := NewsletterSummary{
+		ID:          n.ID,
+		MetadataURL: n.MetadataURL,
+		Title:       n.Title,
+		Status:      n.Status,
+		Total:       len(n.Recipients),
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+	}
+	for _, d := range n.Recipients {
+		switch d.Status {
+		case DeliverySent:
+			out.Sent++
+		case DeliveryFailed:
+			out.Failed++
+		default:
+			out.Pending++
+		}
+	}
+	return out
+}
+
+// EnqueueNewsletter snapshots the site's subscriber list and queues an
+// announcement for the post at metadataURL. Any code derives a wallet that
+// can publish, so only the server admin code may mail the subscribers.
+// Enqueuing the same post twice returns the existing newsletter instead of
+// mailing everyone again.
+func (s *Service) EnqueueNewsletter(ctx context.Context, adminCode, metadataURL string) (NewsletterSummary, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return NewsletterSummary{}, fmt.Errorf("firebase client is nil")
+	}
+
+	if err := s.AuthorizeServerAdmin(adminCode); err != nil {
+		return NewsletterSummary{}, err
+	}
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return NewsletterSummary{}, err
+	}
+
+	metadataURL = strings.TrimSpace(metadataURL)
+	if metadataURL == "" {
+		return NewsletterSummary{}, apperr.Post.ErrInvalidRequest
+	}
+	id := newsletterID(metadataURL)
+	if existing, exists, err := firebase.Read[Newsletter](ctx, s.fb, s.newsletterPath(id)); err != nil {
+		return NewsletterSummary{}, fmt.Errorf("read newsletter: %w", err)
+	} else if exists {
+		return existing.summary(), nil
+	}
+
+	meta, err := s.fetchMetadata(ctx, metadataURL)
+	if err != nil {
+		return NewsletterSummary{}, err
+	}
+	p := mapMetadataToPost(meta, "", metadataURL)
+
+	emails, err := s.subscriberEmails(ctx)
+	if err != nil {
+		return NewsletterSummary{}, err
+	}
+
+	recipients := make(map[string]Delivery, len(emails))
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		recipients[recipientKey(email)] = Delivery{Email: email, Status: DeliveryPending}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	n := Newsletter{
+		ID:          id,
+		Owner:       ownerAddr.Hex(),
+		MetadataURL: metadataURL,
+		Title:       p.Title,
+		Subject:     fmt.Sprintf("[%s] %s", s.siteTitle(), p.Title),
+		Body:        s.announcementBody(p),
+		Status:      NewsletterPending,
+		Recipients:  recipients,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if len(recipients) == 0 {
+		n.Status = NewsletterCompleted
+	}
+	if err := s.writeNewsletter(ctx, n); err != nil {
+		return NewsletterSummary{}, err
+	}
+	return n.summary(), nil
+}
+
+// ListNewsletters returns the owner's newsletters, newest first.
+func (s *Service) ListNewsletters(ctx context.Context, adminCode string) ([]NewsletterSummary, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.readNewsletters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]NewsletterSummary, 0, len(all))
+	for _, n := range all {
+		if n.Owner == ownerAddr.Hex() {
+			out = append(out, n.summary())
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt > out[j].CreatedAt })
+	return out, nil
+}
+
+// RetryNewsletter puts failed deliveries back in the queue.
+func (s *Service) RetryNewsletter(ctx context.Context, adminCode, id string) (NewsletterSummary, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return NewsletterSummary{}, err
+	}
+	id = strings.TrimSpace(id)
+	if id == "" || toPathSegment(id) != id {
+		return NewsletterSummary{}, apperr.Post.ErrNewsletterNotFound
+	}
+	n, exists, err := firebase.Read[Newsletter](ctx, s.fb, s.newsletterPath(id))
+	if err != nil {
+		return NewsletterSummary{}, fmt.Errorf("read newsletter: %w", err)
+	}
+	if !exists || n.Owner != ownerAddr.Hex() {
+		return NewsletterSummary{}, apperr.Post.ErrNewsletterNotFound
+	}
+
+	for key, d := range n.Recipients {
+		if d.Status == DeliveryFailed {
+			d.Status, d.Attempts, d.Error = DeliveryPending, 0, ""
+			n.Recipients[key] = d
+			n.Status = NewsletterPending
+		}
+	}
+	n.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := s.writeNewsletter(ctx, n); err != nil {
+		return NewsletterSummary{}, err
+	}
+	return n.summary(), nil
+}
+
+// ProcessNewsletters sends up to NEWSLETTER_BATCH_SIZE pending deliveries,
+// waiting NEWSLETTER_SEND_DELAY between messages to stay under Gmail's rate
+// limits. Each delivery is persisted right after its send attempt.
+func (s *Service) ProcessNewsletters(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return fmt.Errorf("firebase client is nil")
+	}
+
+	all, err := s.readNewsletters(ctx)
+	if err != nil {
+		return err
+	}
+	pending := make([]Newsletter, 0, len(all))
+	for _, n := range all {
+		if n.Status == NewsletterPending {
+			pending = append(pending, n)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].CreatedAt < pending[j].CreatedAt })
+
+	return s.deliver(ctx, pending,
+		func(id, key string, d Delivery) error {
+			if err := firebase.Write(ctx, s.fb, s.newsletterPath(id)+"/recipients/"+key, d); err != nil {
+				return fmt.Errorf("write delivery: %w", err)
+			}
+			return nil
+		},
+		func(n Newsletter) error { return s.writeNewsletter(ctx, n) },
+	)
+}
+
+// deliver works through the pending deliveries of newsletters in order,
+// calling saveDelivery after every attempt and complete once a newsletter has
+// nothing left to send. It stops after NEWSLETTER_BATCH_SIZE attempts; the
+// next run resumes from the deliveries that are still pending.
+func (s *Service) deliver(ctx context.Context, newsletters []Newsletter, saveDelivery func(id, key string, d Delivery) error, complete func(n Newsletter) error) error {
+	budget := s.cfg.Newsletter.BatchSize
+	if budget <= 0 {
+		budget = 20
+	}
+	maxAttempts := s.cfg.Newsletter.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	for _, n := range newsletters {
+		keys := make([]string, 0, len(n.Recipients))
+		for key, d := range n.Recipients {
+			if d.Status == DeliveryPending {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if budget == 0 {
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			budget--
+
+			d := n.Recipients[key]
+			d.Attempts++
+			err := s.sendEmail(ctx, s.cfg, googlemail.EmailContent{
+				Recipient: d.Email,
+				Subject:   googlemail.EncodeSubject(n.Subject),
+				Body:      n.Body,
+			})
+			switch {
+			case err == nil:
+				d.Status, d.Error, d.SentAt = DeliverySent, "", time.Now().UTC().Format(time.RFC3339)
+			case d.Attempts >= maxAttempts:
+				d.Status, d.Error = DeliveryFailed, err.Error()
+			default:
+				d.Error = err.Error()
+			}
+			n.Recipients[key] = d
+			if err := saveDelivery(n.ID, key, d); err != nil {
+				return err
+			}
+
+			if delay := s.cfg.Newsletter.SendDelay; delay > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(delay):
+				}
+			}
+		}
+
+		if n.summary().Pending == 0 {
+			n.Status = NewsletterCompleted
+			n.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+			if err := complete(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// subscriberEmails reads the site's subscribers, which are stored under the
+// server owner's wallet whoever publishes the post.
+func (s *Service) subscriberEmails(ctx context.Context) ([]string, error) {
+	ownerAddr, err := s.ownerAddress()
+	if err != nil {
+		return nil, err
+	}
+	accts, err := s.eth.Accounts()
+	if err != nil {
+		return nil, fmt.Errorf("load relayer accounts: %w", err)
+	}
+	relayerAddr, err := eth.AddressFromPrivateKey(accts.Relayer)
+	if err != nil {
+		return nil, fmt.Errorf("relayer address: %w", err)
+	}
+	contract, _, err := s.eth.Contract(types.SUBSCRIBERSTORAGE)
+	if err != nil {
+		return nil, fmt.Errorf("bind subscriber storage: %w", err)
+	}
+
+	var emails []string
+	out := []any{&emails}
+	if err := contract.Call(&bind.CallOpts{Context: ctx, From: relayerAddr}, &out, "getSubscriberEmails", ownerAddr); err != nil {
+		return nil, apperr.Wrap(err, apperr.Subscriber.ErrGetSubscribers.Code, "getSubscriberEmails", apperr.Subscriber.ErrGetSubscribers.Status)
+	}
+	return emails, nil
+}
+
+func (s *Service) siteTitle() string {
+	if t := strings.TrimSpace(s.cfg.Site.Title); t != "" {
+		return t
+	}
+	return "IN Labs"
+}
+
+func (s *Service) announcementBody(p Post) string {
+	link := p.ExternalURL
+	if link == "" {
+		link = absolutePostURL(strings.TrimSpace(s.cfg.Site.URL), p)
+	}
+	summary := p.Summary
+	if summary == "" {
+		summary = p.Description
+	}
+
+	parts := []string{
+		"<html><body>",
+		`<div style="text-align:center; margin-bottom:16px;">`,
+		`<img src="https://in-labs.s3.ap-northeast-2.amazonaws.com/images/in.png" alt="IN Labs" style="max-width:160px;height:auto;" />`,
+		"</div>",
+		fmt.Sprintf("<p>%s에 새 글이 올라왔습니다.</p>", html.EscapeString(s.siteTitle())),
+		fmt.Sprintf("<h2>%s</h2>", html.EscapeString(p.Title)),
+	}
+	if p.Image != "" {
+		parts = append(parts, fmt.Sprintf(`<p><img src="%s" alt="" style="max-width:100%%;height:auto;" /></p>`, html.EscapeString(p.Image)))
+	}
+	if summary != "" {
+		parts = append(parts, fmt.Sprintf("<p>%s</p>", html.EscapeString(summary)))
+	}
+	if link != "" {
+		parts = append(parts, fmt.Sprintf(`<p><a href="%s">글 읽으러 가기</a></p>`, html.EscapeString(link)))
+	}
+	parts = append(parts, "</body></html>")
+	return strings.Join(parts, "")
+}
+
+func (s *Service) newslettersPath() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "newsletters/" + env
+}
+
+func (s *Service) newsletterPath(id string) string {
+	return s.newslettersPath() + "/" + id
+}
+
+func (s *Service) readNewsletters(ctx context.Context) (map[string]Newsletter, error) {
+	all, exists, err := firebase.Read[map[string]Newsletter](ctx, s.fb, s.newslettersPath())
+	if err != nil {
+		return nil, fmt.Errorf("read newsletters: %w", err)
+	}
+	if !exists {
+		return map[string]Newsletter{}, nil
+	}
+	return all, nil
+}
+
+func (s *Service) writeNewsletter(ctx context.Context, n Newsletter) error {
+	if err := firebase.Write(ctx, s.fb, s.newsletterPath(n.ID), n); err != nil {
+		return fmt.Errorf("write newsletter: %w", err)
+	}
+	return nil
+}
+
+func newsletterID(metadataURL string) string {
+	sum := sha256.Sum256([]byte(metadataURL))
+	return "nl-" + hex.EncodeToString(sum[:8])
+}
+
+func recipientKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:12])
+}
+
+// notifyAfterPublish enqueues a newsletter for a post that was just published
+// in the background, where there is no caller to return the error to.
+func (s *Service) notifyAfterPublish(ctx context.Context, adminCode, metadataURL string) {
+	if _, err := s.EnqueueNewsletter(ctx, adminCode, metadataURL); err != nil {
+		log.Printf("newsletter for %s: %v", metadataURL, err)
+	}
+}
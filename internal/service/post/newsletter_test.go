@@ -0,0 +1,121 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"in-server/pkg/config"
+	googlemail "in-server/pkg/google"
+)
+
+func TestAnnouncementBodyEscapes(t *testing.T) {
+	s := &Service{}
+	s.cfg.Site.Title = "IN Labs"
+	body := s.announcementBody(Post{
+		Title:       `<script>alert("x")</script>`,
+		Summary:     "Tom & Jerry",
+		ExternalURL: `https://in.example/dev-lab/a?b="c"`,
+	})
+
+	if strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Fatalf("title not escaped:\n%s", body)
+	}
+	if !strings.Contains(body, "Tom &amp; Jerry") || !strings.Contains(body, `href="https://in.example/dev-lab/a?b=&#34;c&#34;"`) {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+}
+
+func TestNewsletterSummaryCounts(t *testing.T) {
+	n := Newsletter{Recipients: map[string]Delivery{
+		recipientKey("a@example.com"): {Status: DeliverySent},
+		recipientKey("b@example.com"): {Status: DeliveryFailed},
+		recipientKey("c@example.com"): {Status: DeliveryPending},
+	}}
+	got := n.summary()
+	if got.Total != 3 || got.Sent != 1 || got.Failed != 1 || got.Pending != 1 {
+		t.Fatalf("unexpected summary: %+v", got)
+	}
+	if recipientKey(" A@Example.com ") != recipientKey("a@example.com") {
+		t.Fatalf("recipient key should ignore case and whitespace")
+	}
+}
+
+func TestDeliverNewslettersBatchesAndResumes(t *testing.T) {
+	var sent []string
+	s := &Service{sendEmail: func(_ context.Context, _ config.Config, content googlemail.EmailContent) error {
+		sent = append(sent, content.Recipient)
+		if content.Recipient == "c@example.com" {
+			return errors.New("mailbox full")
+		}
+		return nil
+	}}
+	s.cfg.Newsletter.BatchSize = 2
+	s.cfg.Newsletter.MaxAttempts = 2
+	s.cfg.Newsletter.SendDelay = 5 * time.Millisecond
+
+	newsletters := []Newsletter{
+		{ID: "nl-a", Status: NewsletterPending, Recipients: map[string]Delivery{
+			"a": {Email: "a@example.com", Status: DeliveryPending},
+			"b": {Email: "b@example.com", Status: DeliverySent},
+			"c": {Email: "c@example.com", Status: DeliveryPending},
+			"d": {Email: "d@example.com", Status: DeliveryPending},
+		}},
+		{ID: "nl-b", Status: NewsletterPending, Recipients: map[string]Delivery{
+			"e": {Email: "e@example.com", Status: DeliveryPending},
+		}},
+	}
+	saved := map[string]Delivery{}
+	completed := map[string]bool{}
+	run := func() {
+		t.Helper()
+		err := s.deliver(context.Background(), newsletters,
+			func(id, key string, d Delivery) error {
+				saved[id+"/"+key] = d
+				return nil
+			},
+			func(n Newsletter) error {
+				completed[n.ID] = n.Status == NewsletterCompleted
+				return nil
+			},
+		)
+		if err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+
+	start := time.Now()
+	run()
+	if elapsed := time.Since(start); elapsed < 2*s.cfg.Newsletter.SendDelay {
+		t.Errorf("two sends took %v, want at least two delays", elapsed)
+	}
+	if strings.Join(sent, ",") != "a@example.com,c@example.com" || len(saved) != 2 || len(completed) != 0 {
+		t.Fatalf("first batch: sent %v, saved %v, completed %v", sent, saved, completed)
+	}
+	if d := saved["nl-a/c"]; d.Status != DeliveryPending || d.Attempts != 1 || d.Error == "" {
+		t.Fatalf("first failure should stay pending: %+v", d)
+	}
+
+	sent = nil
+	run()
+	if strings.Join(sent, ",") != "c@example.com,d@example.com" {
+		t.Fatalf("second batch sent %v", sent)
+	}
+	if d := saved["nl-a/c"]; d.Status != DeliveryFailed || d.Attempts != 2 {
+		t.Fatalf("delivery should fail after max attempts: %+v", d)
+	}
+	if !completed["nl-a"] || completed["nl-b"] {
+		t.Fatalf("completed = %v", completed)
+	}
+
+	sent = nil
+	run()
+	if strings.Join(sent, ",") != "e@example.com" || !completed["nl-b"] {
+		t.Fatalf("third batch sent %v, completed %v", sent, completed)
+	}
+	if got := newsletters[0].Recipients["b"]; got.Attempts != 0 {
+		t.Fatalf("already sent delivery was retried: %+v", got)
+	}
+}
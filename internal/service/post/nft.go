@@ -14,4 +14,7 @@ type NftMetadata struct {
 	Image       string         `json:"image,omitempty"`
 	ExternalURL string         `json:"external_url"`
 	Attributes  []NftAttribute `json:"attributes"`
+	// Generated lists the attributes enrichMetadata derived rather than the
+	// editor wrote; they are recomputed on every publish.
+	Generated []string `json:"generated,omitempty"`
 }
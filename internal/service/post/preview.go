@@ -0,0 +1,273 @@
+package post
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/markdown"
+)
+
+const (
+	DefaultPreviewTTL = 72 * time.Hour
+	MaxPreviewTTL     = 30 * 24 * time.Hour
+)
+
+type PreviewKind string
+
+const (
+	PreviewDraft    PreviewKind = "draft"
+	PreviewRevision PreviewKind = "revision"
+)
+
+// previewClaims is the signed body of a preview token. It names exactly one
+// object: a draft of Owner, or an immutable metadata revision.
+type previewClaims struct {
+	Kind     PreviewKind `json:"k"`
+	Owner    string      `json:"o"`
+	DraftID  string      `json:"d,omitempty"`
+	Key      string      `json:"r,omitempty"`
+	TokenID  string      `json:"t,omitempty"`
+	Revision int         `json:"n,omitempty"`
+	Expires  int64       `json:"exp"`
+}
+
+type PreviewRequest struct {
+	// DraftID previews a draft. Otherwise Ref and Revision select a
+	// revision of a published post.
+	DraftID  string
+	Ref      string
+	Revision int
+	TTL      time.Duration
+}
+
+type PreviewLink struct {
+	Token     string      `json:"token"`
+	Path      string      `json:"path"`
+	Kind      PreviewKind `json:"kind"`
+	ExpiresAt string      `json:"expiresAt"`
+}
+
+type Preview struct {
+	Kind      PreviewKind `json:"kind"`
+	Revision  int         `json:"revision,omitempty"`
+	ExpiresAt string      `json:"expiresAt"`
+	Post      Post        `json:"post"`
+}
+
+// CreatePreview signs an expiring link to a draft or revision owned by the
+// admin wallet.
+func (s *Service) CreatePreview(ctx context.Context, adminCode string, req PreviewRequest) (PreviewLink, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return PreviewLink{}, err
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = DefaultPreviewTTL
+	}
+	if ttl < 0 || ttl > MaxPreviewTTL {
+		return PreviewLink{}, apperr.Post.ErrInvalidPreview
+	}
+	claims := previewClaims{Owner: ownerAddr.Hex(), Expires: time.Now().Add(ttl).Unix()}
+
+	if draftID := strings.TrimSpace(req.DraftID); draftID != "" {
+		key, err := s.draftKey(ownerAddr, draftID)
+		if err != nil {
+			return PreviewLink{}, err
+		}
+		if _, err := s.readDraft(ctx, key); err != nil {
+			return PreviewLink{}, err
+		}
+		claims.Kind = PreviewDraft
+		claims.DraftID = draftID
+	} else {
+		if req.Revision <= 0 {
+			return PreviewLink{}, apperr.Post.ErrInvalidPreview
+		}
+		p, _, err := s.resolveOwned(ctx, ownerAddr, req.Ref)
+		if err != nil {
+			return PreviewLink{}, err
+		}
+		manifest, err := s.readManifest(ctx, extractKeyFromMetadataURL(p.MetadataURL))
+		if errors.Is(err, errObjectNotFound) {
+			return PreviewLink{}, apperr.Post.ErrRevisionNotFound
+		}
+		if err != nil {
+			return PreviewLink{}, err
+		}
+		rev, ok := findRevision(manifest, req.Revision)
+		if !ok {
+			return PreviewLink{}, apperr.Post.ErrRevisionNotFound
+		}
+		claims.Kind = PreviewRevision
+		claims.Key = rev.Key
+		claims.TokenID = p.TokenID
+		claims.Revision = rev.Number
+	}
+
+	token, err := s.signPreview(claims)
+	if err != nil {
+		return PreviewLink{}, err
+	}
+	return PreviewLink{
+		Token:     token,
+		Path:      "/preview/" + token,
+		Kind:      claims.Kind,
+		ExpiresAt: time.Unix(claims.Expires, 0).UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// Preview resolves a token from CreatePreview into a post mapped like a
+// published one. Draft previews always show the draft's latest content.
+func (s *Service) Preview(ctx context.Context, token string) (Preview, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	claims, err := s.verifyPreview(token, time.Now())
+	if err != nil {
+		return Preview{}, err
+	}
+
+	var meta metadata
+	switch claims.Kind {
+	case PreviewDraft:
+		key, err := s.draftKey(common.HexToAddress(claims.Owner), claims.DraftID)
+		if err != nil {
+			return Preview{}, err
+		}
+		draft, err := s.readDraft(ctx, key)
+		if err != nil {
+			return Preview{}, err
+		}
+		if meta, err = toMetadata(s.enrichMetadata(normalizeMetadata(draft.Payload))); err != nil {
+			return Preview{}, err
+		}
+	case PreviewRevision:
+		raw, err := s.readObject(ctx, claims.Key)
+		if errors.Is(err, errObjectNotFound) {
+			return Preview{}, apperr.Post.ErrRevisionNotFound
+		}
+		if err != nil {
+			return Preview{}, err
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return Preview{}, fmt.Errorf("decode revision %s: %w", claims.Key, err)
+		}
+	default:
+		return Preview{}, apperr.Post.ErrInvalidPreview
+	}
+
+	p := mapMetadataToPost(meta, claims.TokenID, "")
+	if strings.TrimSpace(p.Content) != "" {
+		rendered, err := markdown.Render(p.Content)
+		if err != nil {
+			return Preview{}, err
+		}
+		p.ContentHTML = rendered.HTML
+		p.TOC = rendered.TOC
+	}
+
+	return Preview{
+		Kind:      claims.Kind,
+		Revision:  claims.Revision,
+		ExpiresAt: time.Unix(claims.Expires, 0).UTC().Format(time.RFC3339),
+		Post:      p,
+	}, nil
+}
+
+// signPreview encodes claims as base64url(json) "." base64url(HMAC-SHA256).
+func (s *Service) signPreview(claims previewClaims) (string, error) {
+	secret, err := s.previewSecret()
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshal preview claims: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(body)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(previewMAC(secret, payload)), nil
+}
+
+func (s *Service) verifyPreview(token string, now time.Time) (previewClaims, error) {
+	secret, err := s.previewSecret()
+	if err != nil {
+		return previewClaims{}, err
+	}
+
+	payload, sig, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return previewClaims{}, apperr.Post.ErrInvalidPreview
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, previewMAC(secret, payload)) {
+		return previewClaims{}, apperr.Post.ErrInvalidPreview
+	}
+	body, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return previewClaims{}, apperr.Post.ErrInvalidPreview
+	}
+
+	var claims previewClaims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return previewClaims{}, apperr.Post.ErrInvalidPreview
+	}
+	if now.Unix() >= claims.Expires {
+		return previewClaims{}, apperr.Post.ErrPreviewExpired
+	}
+	return claims, nil
+}
+
+func (s *Service) previewSecret() ([]byte, error) {
+	secret := strings.TrimSpace(s.cfg.Auth.JWT.AccessSecret)
+	if secret == "" {
+		return nil, apperr.Post.ErrPreviewSecretMissing
+	}
+	return []byte(secret), nil
+}
+
+// previewMAC binds the MAC to its purpose so the access secret cannot be
+// used to forge other HMAC-signed values from preview tokens.
+func previewMAC(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("post-preview:"))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func findRevision(manifest revisionManifest, n int) (Revision, bool) {
+	for _, rev := range manifest.Revisions {
+		if rev.Number == n {
+			return rev, true
+		}
+	}
+	return Revision{}, false
+}
+
+func toMetadata(payload NftMetadata) (metadata, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return metadata{}, fmt.Errorf("marshal metadata: %w", err)
+	}
+	var meta metadata
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return metadata{}, fmt.Errorf("decode metadata: %w", err)
+	}
+	return meta, nil
+}
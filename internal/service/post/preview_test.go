@@ -0,0 +1,54 @@
+package post
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/config"
+)
+
+func TestPreviewToken(t *testing.T) {
+	var cfg config.Config
+	cfg.Auth.JWT.AccessSecret = "secret"
+	s := &Service{cfg: cfg}
+
+	now := time.Now()
+	claims := previewClaims{Kind: PreviewDraft, Owner: "0xabc", DraftID: "my-draft", Expires: now.Add(time.Hour).Unix()}
+	token, err := s.signPreview(claims)
+	if err != nil {
+		t.Fatalf("signPreview: %v", err)
+	}
+
+	got, err := s.verifyPreview(token, now)
+	if err != nil {
+		t.Fatalf("verifyPreview: %v", err)
+	}
+	if got != claims {
+		t.Fatalf("claims = %+v, want %+v", got, claims)
+	}
+
+	if _, err := s.verifyPreview(token, now.Add(2*time.Hour)); !errors.Is(err, apperr.Post.ErrPreviewExpired) {
+		t.Errorf("expired token err = %v", err)
+	}
+
+	payload, sig, _ := strings.Cut(token, ".")
+	forged, _ := s.signPreview(previewClaims{Kind: PreviewDraft, Owner: "0xdef", DraftID: "other", Expires: claims.Expires})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	for _, bad := range []string{"", payload, forgedPayload + "." + sig, payload + ".AAAA"} {
+		if _, err := s.verifyPreview(bad, now); !errors.Is(err, apperr.Post.ErrInvalidPreview) {
+			t.Errorf("verifyPreview(%q) err = %v", bad, err)
+		}
+	}
+
+	other := &Service{}
+	other.cfg.Auth.JWT.AccessSecret = "rotated"
+	if _, err := other.verifyPreview(token, now); !errors.Is(err, apperr.Post.ErrInvalidPreview) {
+		t.Errorf("token verified with another secret: %v", err)
+	}
+	if _, err := (&Service{}).verifyPreview(token, now); !errors.Is(err, apperr.Post.ErrPreviewSecretMissing) {
+		t.Errorf("missing secret err = %v", err)
+	}
+}
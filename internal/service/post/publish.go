@@ -30,11 +30,8 @@ func (s *Service) Publish(ctx context.Context, adminCode string, payload NftMeta
 		return "", apperr.Post.ErrAdminCodeMissing
 	}
 
-	if strings.TrimSpace(payload.Name) == "" ||
-		strings.TrimSpace(payload.Description) == "" ||
-		strings.TrimSpace(payload.ExternalURL) == "" ||
-		len(payload.Attributes) == 0 {
-		return "", apperr.Post.ErrInvalidBody
+	if err := ValidateMetadata(payload); err != nil {
+		return "", err
 	}
 
 	pk, ownerAddr, err := s.eth.Wallet(adminCode)
@@ -42,16 +39,9 @@ func (s *Service) Publish(ctx context.Context, adminCode string, payload NftMeta
 		return "", apperr.Wrap(err, apperr.Post.ErrInvalidAdminCode.Code, apperr.Post.ErrInvalidAdminCode.Message, apperr.Post.ErrInvalidAdminCode.Status)
 	}
 
-	labSegment := toPathSegment(attrValue(payload.Attributes, "Lab"))
-	if labSegment == "" {
-		labSegment = "lab"
-	}
-	slugSegment := toPathSegment(attrValue(payload.Attributes, "Slug"))
-	if slugSegment == "" {
-		slugSegment = "post"
-	}
+	labSegment, slugSegment := postSegments(payload)
 
-	normalized := normalizeMetadata(payload)
+	normalized := s.enrichMetadata(normalizeMetadata(payload))
 
 	metadataURL = strings.TrimSpace(metadataURL)
 	existingKey := ""
@@ -59,7 +49,9 @@ func (s *Service) Publish(ctx context.Context, adminCode string, payload NftMeta
 		existingKey = extractKeyFromMetadataURL(metadataURL)
 	}
 
-	posts, err := s.listByOwner(ctx, ownerAddr)
+	// A cached listing can miss a post published moments ago, so the
+	// duplicate check reads PostStorage directly.
+	posts, err := s.loadByOwner(ctx, ownerAddr)
 	if err != nil {
 		return "", err
 	}
@@ -80,6 +72,11 @@ func (s *Service) Publish(ctx context.Context, adminCode string, payload NftMeta
 		return "", apperr.Post.ErrDuplicatePost
 	}
 
+	normalized, err = s.resolveRelatedLinks(normalized, posts)
+	if err != nil {
+		return "", err
+	}
+
 	resolvedURL, isUpdate, err := s.saveMetadata(ctx, ownerAddr.Hex(), labSegment, normalized, metadataURL, existingKey)
 	if err != nil {
 		return "", err
@@ -98,6 +95,8 @@ func (s *Service) Publish(ctx context.Context, adminCode string, payload NftMeta
 		}
 	}
 
+	s.cache.invalidate(ownerAddr, resolvedURL, metadataURL)
+	s.rememberPublisher(ctx, ownerAddr)
 	return resolvedURL, nil
 }
 
@@ -115,6 +114,7 @@ func normalizeMetadata(payload NftMetadata) NftMetadata {
 		}
 		var raw string
 		if err := json.Unmarshal(attr.Value, &raw); err != nil {
+			outAttrs = append(outAttrs, attr)
 			continue
 		}
 		cleaned := strings.Join(strings.Fields(raw), " ")
@@ -149,11 +149,24 @@ func (s *Service) saveMetadata(ctx context.Context, addressHex, labSegment strin
 		return "", false, fmt.Errorf("marshal metadata: %w", err)
 	}
 
+	manifest, err := s.prepareRevision(ctx, key, data, isUpdate, "")
+	if err != nil {
+		return "", false, err
+	}
+
 	uploadedURL, err := awss3.PutObject(ctx, s.cfg, key, bytes.NewReader(data), "application/json", "")
 	if err != nil {
 		return "", false, apperr.Wrap(err, apperr.Post.ErrUploadMetadata.Code, apperr.Post.ErrUploadMetadata.Message, apperr.Post.ErrUploadMetadata.Status)
 	}
 
+	if err := s.writeManifest(ctx, manifest); err != nil {
+		return "", false, err
+	}
+
+	if err := s.saveRenderedContent(ctx, key, payload); err != nil {
+		return "", false, err
+	}
+
 	return uploadedURL, isUpdate, nil
 }
 
@@ -0,0 +1,234 @@
+package post
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/apperr"
+)
+
+const (
+	SortNewest = "newest"
+	SortOldest = "oldest"
+	SortTitle  = "title"
+
+	MaxListLimit = 100
+)
+
+type ListQuery struct {
+	// Owner lists another wallet's posts; zero means the server admin wallet.
+	Owner    common.Address
+	Lab      string
+	Category string
+	Tag      string
+	Q        string
+	Before   time.Time
+	After    time.Time
+	Sort     string
+	Limit    int
+	Cursor   string
+}
+
+type ListPage struct {
+	Items      []Post `json:"data"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type listCursor struct {
+	After  string `json:"after,omitempty"`
+	Offset int    `json:"offset"`
+}
+
+// Query lists posts and applies q on top of the mapped results. A zero Limit
+// returns every matching post.
+func (s *Service) Query(ctx context.Context, q ListQuery) (ListPage, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr := q.Owner
+	if ownerAddr == (common.Address{}) {
+		var err error
+		if ownerAddr, err = s.ownerAddress(); err != nil {
+			return ListPage{}, err
+		}
+	}
+
+	posts, err := s.listByOwner(ctx, ownerAddr)
+	if err != nil {
+		return ListPage{}, err
+	}
+	page, err := paginatePosts(posts, q)
+	if err != nil {
+		return ListPage{}, err
+	}
+	s.attachAuthor(ctx, ownerAddr, page.Items)
+	s.attachReactions(ctx, page.Items)
+	s.attachViews(ctx, page.Items)
+	s.attachSeries(ctx, page.Items)
+	return page, nil
+}
+
+func paginatePosts(posts []Post, q ListQuery) (ListPage, error) {
+	if q.Limit < 0 || q.Limit > MaxListLimit {
+		return ListPage{}, apperr.Post.ErrInvalidQuery
+	}
+
+	filtered := filterPosts(posts, q)
+	if err := sortPosts(filtered, q.Sort); err != nil {
+		return ListPage{}, err
+	}
+
+	start := 0
+	if strings.TrimSpace(q.Cursor) != "" {
+		cur, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return ListPage{}, err
+		}
+		start = cur.Offset
+		if cur.After != "" {
+			for i, p := range filtered {
+				if p.TokenID == cur.After {
+					start = i + 1
+					break
+				}
+			}
+		}
+	}
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+
+	end := len(filtered)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	page := ListPage{
+		Items: filtered[start:end],
+		Total: len(filtered),
+	}
+	if end < len(filtered) && end > start {
+		page.NextCursor = encodeCursor(listCursor{After: filtered[end-1].TokenID, Offset: end})
+	}
+	return page, nil
+}
+
+func filterPosts(posts []Post, q ListQuery) []Post {
+	lab := toPathSegment(q.Lab)
+	category := strings.ToLower(strings.TrimSpace(q.Category))
+	tag := strings.ToLower(strings.TrimSpace(q.Tag))
+	needle := strings.ToLower(strings.TrimSpace(q.Q))
+
+	out := make([]Post, 0, len(posts))
+	for _, p := range posts {
+		if lab != "" && p.LabSegment != lab && toPathSegment(p.LabName) != lab {
+			continue
+		}
+		if category != "" && strings.ToLower(p.Category) != category {
+			continue
+		}
+		if tag != "" && !hasTag(p.Tags, tag) {
+			continue
+		}
+		if needle != "" && !matchesText(p, needle) {
+			continue
+		}
+		if !q.Before.IsZero() || !q.After.IsZero() {
+			published := parsePublishedAt(p.PublishedAt)
+			if published.IsZero() {
+				continue
+			}
+			if !q.Before.IsZero() && !published.Before(q.Before) {
+				continue
+			}
+			if !q.After.IsZero() && !published.After(q.After) {
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func sortPosts(posts []Post, order string) error {
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "", SortNewest:
+		sort.SliceStable(posts, func(i, j int) bool {
+			return parsePublishedAt(posts[i].PublishedAt).After(parsePublishedAt(posts[j].PublishedAt))
+		})
+	case SortOldest:
+		sort.SliceStable(posts, func(i, j int) bool {
+			return parsePublishedAt(posts[i].PublishedAt).Before(parsePublishedAt(posts[j].PublishedAt))
+		})
+	case SortTitle:
+		sort.SliceStable(posts, func(i, j int) bool {
+			return strings.ToLower(posts[i].Title) < strings.ToLower(posts[j].Title)
+		})
+	default:
+		return apperr.Post.ErrInvalidQuery
+	}
+	return nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range tags {
+		if strings.TrimPrefix(strings.ToLower(t), "#") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesText(p Post, needle string) bool {
+	fields := []string{p.Title, p.Summary, p.Description, p.Content, strings.Join(p.Tags, " ")}
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeCursor(c listCursor) string {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(raw string) (listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return listCursor{}, apperr.Post.ErrInvalidQuery
+	}
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return listCursor{}, apperr.Post.ErrInvalidQuery
+	}
+	return c, nil
+}
+
+// ParseQueryTime accepts RFC3339 timestamps or plain YYYY-MM-DD dates.
+func ParseQueryTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, apperr.Post.ErrInvalidQuery
+}
@@ -0,0 +1,47 @@
+package post
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPaginatePostsFiltersAndPages(t *testing.T) {
+	posts := []Post{
+		{TokenID: "1", Title: "Go tips", LabSegment: "dev-lab", Category: "dev", Tags: []string{"go"}, PublishedAt: "2024-01-01T00:00:00Z"},
+		{TokenID: "2", Title: "Solidity", LabSegment: "dev-lab", Category: "dev", Tags: []string{"web3"}, PublishedAt: "2024-02-01T00:00:00Z"},
+		{TokenID: "3", Title: "Go generics", LabSegment: "dev-lab", Category: "dev", Tags: []string{"Go"}, PublishedAt: "2024-03-01T00:00:00Z"},
+		{TokenID: "4", Title: "Diary", LabSegment: "life-lab", Category: "life", PublishedAt: "2024-04-01T00:00:00Z"},
+	}
+
+	page, err := paginatePosts(posts, ListQuery{Lab: "dev-lab", Tag: "go", Limit: 1})
+	if err != nil {
+		t.Fatalf("paginate: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].TokenID != "3" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	if page.NextCursor == "" {
+		t.Fatalf("expected next cursor")
+	}
+
+	page, err = paginatePosts(posts, ListQuery{Lab: "dev-lab", Tag: "go", Limit: 1, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("paginate second page: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].TokenID != "1" || page.NextCursor != "" {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+
+	after := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	page, err = paginatePosts(posts, ListQuery{After: after, Sort: SortOldest, Q: "o"})
+	if err != nil {
+		t.Fatalf("paginate by date: %v", err)
+	}
+	if page.Total != 2 || page.Items[0].TokenID != "2" || page.Items[1].TokenID != "3" {
+		t.Fatalf("unexpected date filtered page: %+v", page)
+	}
+
+	if _, err := paginatePosts(posts, ListQuery{Cursor: "%%%"}); err == nil {
+		t.Fatalf("expected invalid cursor error")
+	}
+}
@@ -0,0 +1,198 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/crypto"
+	"in-server/pkg/firebase"
+)
+
+type ReactionKind string
+
+const (
+	ReactionLike       ReactionKind = "like"
+	ReactionLove       ReactionKind = "love"
+	ReactionClap       ReactionKind = "clap"
+	ReactionInsightful ReactionKind = "insightful"
+	ReactionFire       ReactionKind = "fire"
+)
+
+// ReactionEmoji maps each accepted kind to the emoji clients render.
+var ReactionEmoji = map[ReactionKind]string{
+	ReactionLike:       "👍",
+	ReactionLove:       "❤️",
+	ReactionClap:       "👏",
+	ReactionInsightful: "💡",
+	ReactionFire:       "🔥",
+}
+
+type Reactions struct {
+	TokenID string                 `json:"tokenId"`
+	Counts  map[ReactionKind]int64 `json:"counts"`
+	// Reacted lists the kinds the requesting visitor has already used.
+	Reacted []ReactionKind `json:"reacted"`
+	// Added reports whether a POST changed the counts.
+	Added bool `json:"added"`
+}
+
+var errAlreadyReacted = errors.New("already reacted")
+
+func ParseReactionKind(raw string) (ReactionKind, error) {
+	kind := ReactionKind(strings.ToLower(strings.TrimSpace(raw)))
+	if _, ok := ReactionEmoji[kind]; !ok {
+		return "", apperr.Post.ErrInvalidReaction
+	}
+	return kind, nil
+}
+
+// React records kind for the post at ref once per visitor. Visitors are
+// identified by a salted hash of their IP, so repeated requests leave the
+// count unchanged.
+func (s *Service) React(ctx context.Context, ref string, kind ReactionKind, ip string) (Reactions, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ReactionEmoji[kind]; !ok {
+		return Reactions{}, apperr.Post.ErrInvalidReaction
+	}
+
+	p, err := s.get(ctx, ref)
+	if err != nil {
+		return Reactions{}, err
+	}
+
+	visitorPath := s.reactionVisitorPath(p.TokenID, crypto.SaltedHashIP(s.cfg.Auth.Hash, ip))
+	added := true
+	_, err = firebase.Transaction(ctx, s.fb, visitorPath, func(cur map[ReactionKind]string, _ bool) (map[ReactionKind]string, error) {
+		if _, ok := cur[kind]; ok {
+			return nil, errAlreadyReacted
+		}
+		if cur == nil {
+			cur = make(map[ReactionKind]string, 1)
+		}
+		cur[kind] = time.Now().UTC().Format(time.RFC3339)
+		return cur, nil
+	})
+	switch {
+	case errors.Is(err, errAlreadyReacted):
+		added = false
+	case err != nil:
+		return Reactions{}, apperr.Wrap(err, apperr.Post.ErrReactionStorage.Code, apperr.Post.ErrReactionStorage.Message, apperr.Post.ErrReactionStorage.Status)
+	default:
+		if _, err := firebase.Increment(ctx, s.fb, s.reactionCountsPath(p.TokenID)+"/"+string(kind), 1); err != nil {
+			s.unmarkReaction(ctx, visitorPath, kind)
+			return Reactions{}, apperr.Wrap(err, apperr.Post.ErrReactionStorage.Code, apperr.Post.ErrReactionStorage.Message, apperr.Post.ErrReactionStorage.Status)
+		}
+	}
+
+	out, err := s.reactionsFor(ctx, p.TokenID, ip)
+	if err != nil {
+		return Reactions{}, err
+	}
+	out.Added = added
+	return out, nil
+}
+
+// unmarkReaction rolls back the visitor marker React wrote when the count
+// could not be incremented, so the visitor can retry.
+func (s *Service) unmarkReaction(ctx context.Context, visitorPath string, kind ReactionKind) {
+	_, err := firebase.Transaction(ctx, s.fb, visitorPath, func(cur map[ReactionKind]string, _ bool) (map[ReactionKind]string, error) {
+		delete(cur, kind)
+		return cur, nil
+	})
+	if err != nil {
+		log.Printf("post reactions: roll back %s marker: %v", kind, err)
+	}
+}
+
+// Reactions returns the post's counts and the kinds the visitor has used.
+func (s *Service) Reactions(ctx context.Context, ref, ip string) (Reactions, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p, err := s.get(ctx, ref)
+	if err != nil {
+		return Reactions{}, err
+	}
+	return s.reactionsFor(ctx, p.TokenID, ip)
+}
+
+func (s *Service) reactionsFor(ctx context.Context, tokenID, ip string) (Reactions, error) {
+	counts, _, err := firebase.Read[map[ReactionKind]int64](ctx, s.fb, s.reactionCountsPath(tokenID))
+	if err != nil {
+		return Reactions{}, apperr.Wrap(err, apperr.Post.ErrReactionStorage.Code, apperr.Post.ErrReactionStorage.Message, apperr.Post.ErrReactionStorage.Status)
+	}
+	mine, _, err := firebase.Read[map[ReactionKind]string](ctx, s.fb, s.reactionVisitorPath(tokenID, crypto.SaltedHashIP(s.cfg.Auth.Hash, ip)))
+	if err != nil {
+		return Reactions{}, apperr.Wrap(err, apperr.Post.ErrReactionStorage.Code, apperr.Post.ErrReactionStorage.Message, apperr.Post.ErrReactionStorage.Status)
+	}
+
+	out := Reactions{TokenID: tokenID, Counts: completeCounts(counts), Reacted: []ReactionKind{}}
+	for _, kind := range reactionKinds() {
+		if _, ok := mine[kind]; ok {
+			out.Reacted = append(out.Reacted, kind)
+		}
+	}
+	return out, nil
+}
+
+// attachReactions sets reaction counts on posts. Counts are decoration, so a
+// read failure is logged and the posts are returned without them.
+func (s *Service) attachReactions(ctx context.Context, posts []Post) {
+	if len(posts) == 0 || s.fb == nil {
+		return
+	}
+
+	var (
+		all map[string]map[ReactionKind]int64
+		err error
+	)
+	if len(posts) == 1 {
+		var counts map[ReactionKind]int64
+		counts, _, err = firebase.Read[map[ReactionKind]int64](ctx, s.fb, s.reactionCountsPath(posts[0].TokenID))
+		all = map[string]map[ReactionKind]int64{posts[0].TokenID: counts}
+	} else {
+		all, _, err = firebase.Read[map[string]map[ReactionKind]int64](ctx, s.fb, s.reactionsRoot("reactionCounts"))
+	}
+	if err != nil {
+		log.Printf("post reactions: %v", err)
+		return
+	}
+	for i := range posts {
+		posts[i].Reactions = completeCounts(all[posts[i].TokenID])
+	}
+}
+
+// completeCounts returns a count for every known kind, dropping unknown ones.
+func completeCounts(counts map[ReactionKind]int64) map[ReactionKind]int64 {
+	out := make(map[ReactionKind]int64, len(ReactionEmoji))
+	for kind := range ReactionEmoji {
+		out[kind] = counts[kind]
+	}
+	return out
+}
+
+func reactionKinds() []ReactionKind {
+	return []ReactionKind{ReactionLike, ReactionLove, ReactionClap, ReactionInsightful, ReactionFire}
+}
+
+func (s *Service) reactionsRoot(root string) string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return root + "/" + env
+}
+
+func (s *Service) reactionCountsPath(tokenID string) string {
+	return s.reactionsRoot("reactionCounts") + "/" + tokenID
+}
+
+func (s *Service) reactionVisitorPath(tokenID, visitorHash string) string {
+	return s.reactionsRoot("reactionVisitors") + "/" + tokenID + "/" + visitorHash
+}
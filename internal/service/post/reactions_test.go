@@ -0,0 +1,34 @@
+package post
+
+import (
+	"errors"
+	"testing"
+
+	"in-server/pkg/apperr"
+)
+
+func TestParseReactionKind(t *testing.T) {
+	kind, err := ParseReactionKind("  Fire ")
+	if err != nil || kind != ReactionFire {
+		t.Fatalf("ParseReactionKind = %q, %v", kind, err)
+	}
+	if _, err := ParseReactionKind("angry"); !errors.Is(err, apperr.Post.ErrInvalidReaction) {
+		t.Fatalf("unknown kind err = %v", err)
+	}
+}
+
+func TestCompleteCountsFillsKnownKinds(t *testing.T) {
+	got := completeCounts(map[ReactionKind]int64{ReactionLike: 3, "unknown": 9})
+	if len(got) != len(ReactionEmoji) {
+		t.Fatalf("got %d kinds, want %d", len(got), len(ReactionEmoji))
+	}
+	if got[ReactionLike] != 3 || got[ReactionFire] != 0 {
+		t.Fatalf("counts = %v", got)
+	}
+	if _, ok := got["unknown"]; ok {
+		t.Fatalf("unknown kind kept: %v", got)
+	}
+	if len(reactionKinds()) != len(ReactionEmoji) {
+		t.Fatalf("reactionKinds out of sync with ReactionEmoji")
+	}
+}
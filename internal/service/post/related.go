@@ -0,0 +1,263 @@
+package post
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/markdown"
+)
+
+const (
+	DefaultRelatedLimit = 5
+	MaxRelatedLimit     = 20
+
+	// Tag overlap is a strong editorial signal; text similarity fills in for
+	// posts that share no tags.
+	relatedTagWeight  = 0.4
+	relatedTextWeight = 0.6
+)
+
+type RelatedPost struct {
+	Post
+	Score float64 `json:"score"`
+}
+
+// relatedIndex holds TF-IDF vectors and tag sets for one owner's listing.
+// fingerprint identifies the listing it was built from.
+type relatedIndex struct {
+	fingerprint string
+	posts       []Post
+	vectors     []map[string]float64
+	tags        []map[string]bool
+}
+
+// Related returns up to limit posts most similar to the post at ref.
+func (s *Service) Related(ctx context.Context, ref string, limit int) ([]RelatedPost, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if limit <= 0 {
+		limit = DefaultRelatedLimit
+	}
+	if limit > MaxRelatedLimit {
+		limit = MaxRelatedLimit
+	}
+
+	// Related posts come from the same author as the target, whoever holds it.
+	p, err := s.get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(p.Owner) {
+		return nil, apperr.Post.ErrPostNotFound
+	}
+	ownerAddr := common.HexToAddress(p.Owner)
+	posts, err := s.listByOwner(ctx, ownerAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := listFingerprint(posts)
+	idx, ok := s.cache.getRelated(ownerAddr, fp)
+	if !ok {
+		idx = buildRelatedIndex(posts, fp)
+		s.cache.putRelated(ownerAddr, idx)
+	}
+
+	target := idx.find(p.TokenID)
+	if target < 0 {
+		return nil, apperr.Post.ErrPostNotFound
+	}
+	return idx.related(target, limit), nil
+}
+
+func (idx *relatedIndex) find(ref string) int {
+	ref = strings.TrimSpace(ref)
+	slug := toPathSegment(ref)
+	for i, p := range idx.posts {
+		if p.TokenID == ref {
+			return i
+		}
+	}
+	for i, p := range idx.posts {
+		if slug != "" && toPathSegment(p.Slug) == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+func (idx *relatedIndex) related(target, limit int) []RelatedPost {
+	out := make([]RelatedPost, 0, len(idx.posts))
+	for i, p := range idx.posts {
+		if i == target {
+			continue
+		}
+		score := relatedTextWeight*cosine(idx.vectors[target], idx.vectors[i]) +
+			relatedTagWeight*jaccard(idx.tags[target], idx.tags[i])
+		if score <= 0 {
+			continue
+		}
+		out = append(out, RelatedPost{Post: p, Score: math.Round(score*1000) / 1000})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return parsePublishedAt(out[i].PublishedAt).After(parsePublishedAt(out[j].PublishedAt))
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func buildRelatedIndex(posts []Post, fingerprint string) *relatedIndex {
+	idx := &relatedIndex{
+		fingerprint: fingerprint,
+		posts:       clonePosts(posts),
+		vectors:     make([]map[string]float64, len(posts)),
+		tags:        make([]map[string]bool, len(posts)),
+	}
+
+	termFreqs := make([]map[string]float64, len(posts))
+	docFreq := make(map[string]int)
+	for i, p := range posts {
+		// The title is repeated so it outweighs a long body.
+		text := strings.Join([]string{p.Title, p.Title, p.Summary, markdown.PlainText(p.Content)}, "\n")
+		tf := make(map[string]float64)
+		for _, tok := range tokenize(text) {
+			tf[tok]++
+		}
+		for tok := range tf {
+			docFreq[tok]++
+		}
+		termFreqs[i] = tf
+
+		tags := make(map[string]bool, len(p.Tags))
+		for _, t := range p.Tags {
+			tags[strings.ToLower(strings.TrimLeft(t, "#"))] = true
+		}
+		idx.tags[i] = tags
+	}
+
+	n := float64(len(posts))
+	for i, tf := range termFreqs {
+		vec := make(map[string]float64, len(tf))
+		var norm float64
+		for tok, count := range tf {
+			w := (1 + math.Log(count)) * (math.Log((1+n)/(1+float64(docFreq[tok]))) + 1)
+			vec[tok] = w
+			norm += w * w
+		}
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for tok := range vec {
+				vec[tok] /= norm
+			}
+		}
+		idx.vectors[i] = vec
+	}
+	return idx
+}
+
+var englishStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "with": true, "this": true,
+	"that": true, "from": true, "you": true, "your": true, "was": true, "were": true,
+	"has": true, "have": true, "not": true, "but": true, "can": true, "will": true,
+	"how": true, "what": true, "into": true, "its": true, "our": true, "about": true,
+	"is": true, "in": true, "of": true, "to": true, "on": true, "an": true, "as": true,
+	"at": true, "by": true, "be": true, "or": true, "it": true, "we": true,
+}
+
+// tokenize splits mixed Korean/English text. Latin words become lowercase
+// terms; runs of Hangul become character bigrams, which match across the
+// particles Korean attaches to nouns without needing a morphological analyzer.
+func tokenize(text string) []string {
+	var (
+		tokens []string
+		word   []rune
+		hangul []rune
+	)
+	flushWord := func() {
+		if len(word) >= 2 {
+			if w := string(word); !englishStopwords[w] {
+				tokens = append(tokens, w)
+			}
+		}
+		word = word[:0]
+	}
+	flushHangul := func() {
+		switch {
+		case len(hangul) == 1:
+			tokens = append(tokens, string(hangul))
+		case len(hangul) > 1:
+			for i := 0; i+1 < len(hangul); i++ {
+				tokens = append(tokens, string(hangul[i:i+2]))
+			}
+		}
+		hangul = hangul[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			flushWord()
+			hangul = append(hangul, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHangul()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHangul()
+		}
+	}
+	flushWord()
+	flushHangul()
+	return tokens
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for tok, w := range a {
+		dot += w * b[tok]
+	}
+	return dot
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for t := range a {
+		if b[t] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func listFingerprint(posts []Post) string {
+	h := sha256.New()
+	for _, p := range posts {
+		h.Write([]byte(p.TokenID))
+		h.Write([]byte{0})
+		h.Write([]byte(p.MetadataURL))
+		h.Write([]byte{0})
+		h.Write([]byte(p.UpdatedAt))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
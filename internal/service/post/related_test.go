@@ -0,0 +1,44 @@
+package post
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeMixedText(t *testing.T) {
+	got := tokenize("The Go 서버를 만들기, a Test")
+	want := []string{"go", "서버", "버를", "만들", "들기", "test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestRelatedRanksByTagsAndText(t *testing.T) {
+	posts := []Post{
+		{TokenID: "1", Slug: "go-server", Title: "Go 서버 만들기", Tags: []string{"go", "backend"}, Content: "gin 으로 서버를 만든다"},
+		{TokenID: "2", Slug: "go-testing", Title: "Go 테스트", Tags: []string{"go"}, Content: "서버 테스트 작성"},
+		{TokenID: "3", Slug: "baking", Title: "Sourdough bread", Tags: []string{"food"}, Content: "flour and water"},
+		{TokenID: "4", Slug: "gin-router", Title: "Gin router internals", Tags: []string{"backend"}, Content: "서버 라우팅"},
+	}
+	idx := buildRelatedIndex(posts, listFingerprint(posts))
+
+	target := idx.find("go-server")
+	if target != 0 {
+		t.Fatalf("find = %d, want 0", target)
+	}
+	got := idx.related(target, 5)
+	if len(got) != 2 {
+		t.Fatalf("related returned %d posts, want 2: %+v", len(got), got)
+	}
+	for _, p := range got {
+		if p.TokenID == "1" || p.TokenID == "3" {
+			t.Fatalf("unexpected related post %s", p.TokenID)
+		}
+	}
+	if got[0].Score < got[1].Score {
+		t.Fatalf("results not sorted by score: %+v", got)
+	}
+	if limited := idx.related(target, 1); len(limited) != 1 {
+		t.Fatalf("limit not applied: %d", len(limited))
+	}
+}
@@ -0,0 +1,339 @@
+package post
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"in-server/pkg/apperr"
+	awss3 "in-server/pkg/aws/s3"
+)
+
+var errObjectNotFound = errors.New("s3 object not found")
+
+type Revision struct {
+	Number    int    `json:"number"`
+	Key       string `json:"key"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"createdAt"`
+	Note      string `json:"note,omitempty"`
+	Current   bool   `json:"current,omitempty"`
+}
+
+// revisionManifest lives next to the live metadata object and records every
+// immutable revision written for it.
+type revisionManifest struct {
+	LiveKey   string     `json:"liveKey"`
+	Current   int        `json:"current"`
+	Revisions []Revision `json:"revisions"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+func revisionKey(liveKey string, n int) string {
+	return fmt.Sprintf("%s.rev-%d.json", strings.TrimSuffix(liveKey, ".json"), n)
+}
+
+func manifestKey(liveKey string) string {
+	return strings.TrimSuffix(liveKey, ".json") + ".manifest.json"
+}
+
+// prepareRevision stores data as the next immutable revision of liveKey before
+// the live object is overwritten. Objects written before revisions existed are
+// snapshotted first so the previous content is never lost.
+func (s *Service) prepareRevision(ctx context.Context, liveKey string, data []byte, isUpdate bool, note string) (revisionManifest, error) {
+	manifest, err := s.readManifest(ctx, liveKey)
+	if err != nil && !errors.Is(err, errObjectNotFound) {
+		return revisionManifest{}, err
+	}
+	if errors.Is(err, errObjectNotFound) {
+		manifest = revisionManifest{LiveKey: liveKey}
+		if isUpdate {
+			previous, err := s.readObject(ctx, liveKey)
+			if err != nil && !errors.Is(err, errObjectNotFound) {
+				return revisionManifest{}, err
+			}
+			if err == nil {
+				if _, err := s.appendRevision(ctx, &manifest, previous, "snapshot before first revision"); err != nil {
+					return revisionManifest{}, err
+				}
+			}
+		}
+	}
+
+	if _, err := s.appendRevision(ctx, &manifest, data, note); err != nil {
+		return revisionManifest{}, err
+	}
+	return manifest, nil
+}
+
+func (s *Service) appendRevision(ctx context.Context, manifest *revisionManifest, data []byte, note string) (Revision, error) {
+	n := 1
+	if len(manifest.Revisions) > 0 {
+		n = manifest.Revisions[len(manifest.Revisions)-1].Number + 1
+	}
+	key := revisionKey(manifest.LiveKey, n)
+	url, err := awss3.PutObject(ctx, s.cfg, key, bytes.NewReader(data), "application/json", "")
+	if err != nil {
+		return Revision{}, apperr.Wrap(err, apperr.Post.ErrUploadMetadata.Code, "save metadata revision", apperr.Post.ErrUploadMetadata.Status)
+	}
+	rev := Revision{
+		Number:    n,
+		Key:       key,
+		URL:       url,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Note:      note,
+	}
+	manifest.Revisions = append(manifest.Revisions, rev)
+	manifest.Current = n
+	return rev, nil
+}
+
+func (s *Service) readManifest(ctx context.Context, liveKey string) (revisionManifest, error) {
+	raw, err := s.readObject(ctx, manifestKey(liveKey))
+	if err != nil {
+		return revisionManifest{}, err
+	}
+	var manifest revisionManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return revisionManifest{}, fmt.Errorf("decode revision manifest: %w", err)
+	}
+	if manifest.LiveKey == "" {
+		manifest.LiveKey = liveKey
+	}
+	return manifest, nil
+}
+
+// discardMetadata deletes a live metadata object together with its rendered
+// content, revisions and manifest. Failures are logged; the caller has
+// already failed and only cleans up.
+func (s *Service) discardMetadata(ctx context.Context, liveKey string) {
+	if liveKey == "" {
+		return
+	}
+	keys := []string{liveKey, renderedKey(liveKey), manifestKey(liveKey)}
+	if manifest, err := s.readManifest(ctx, liveKey); err == nil {
+		for _, rev := range manifest.Revisions {
+			keys = append(keys, rev.Key)
+		}
+	}
+	for _, key := range keys {
+		if err := awss3.DeleteObject(ctx, s.cfg, key, ""); err != nil {
+			log.Printf("discard metadata %s: %v", key, err)
+		}
+	}
+}
+
+func (s *Service) writeManifest(ctx context.Context, manifest revisionManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("marshal revision manifest: %w", err)
+	}
+	if _, err := awss3.PutObject(ctx, s.cfg, manifestKey(manifest.LiveKey), bytes.NewReader(data), "application/json", ""); err != nil {
+		return apperr.Wrap(err, apperr.Post.ErrUploadMetadata.Code, "save revision manifest", apperr.Post.ErrUploadMetadata.Status)
+	}
+	return nil
+}
+
+// Revisions lists the stored revisions of a post, oldest first.
+func (s *Service) Revisions(ctx context.Context, ref string) ([]Revision, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	p, err := s.get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := s.readManifest(ctx, extractKeyFromMetadataURL(p.MetadataURL))
+	if errors.Is(err, errObjectNotFound) {
+		return []Revision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Revision, len(manifest.Revisions))
+	for i, rev := range manifest.Revisions {
+		rev.Current = rev.Number == manifest.Current
+		out[i] = rev
+	}
+	return out, nil
+}
+
+// revisionMetadata loads the metadata stored in revision n of the manifest.
+func (s *Service) revisionMetadata(ctx context.Context, manifest revisionManifest, n int) (NftMetadata, []byte, error) {
+	for _, rev := range manifest.Revisions {
+		if rev.Number != n {
+			continue
+		}
+		raw, err := s.readObject(ctx, rev.Key)
+		if errors.Is(err, errObjectNotFound) {
+			return NftMetadata{}, nil, apperr.Post.ErrRevisionNotFound
+		}
+		if err != nil {
+			return NftMetadata{}, nil, err
+		}
+		var meta NftMetadata
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return NftMetadata{}, nil, fmt.Errorf("decode revision %d: %w", n, err)
+		}
+		return meta, raw, nil
+	}
+	return NftMetadata{}, nil, apperr.Post.ErrRevisionNotFound
+}
+
+// DiffRevisions compares two revisions field by field. Attributes are keyed
+// by trait type.
+func (s *Service) DiffRevisions(ctx context.Context, ref string, from, to int) (RevisionDiff, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	p, err := s.get(ctx, ref)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	manifest, err := s.readManifest(ctx, extractKeyFromMetadataURL(p.MetadataURL))
+	if errors.Is(err, errObjectNotFound) {
+		return RevisionDiff{}, apperr.Post.ErrRevisionNotFound
+	}
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	fromMeta, _, err := s.revisionMetadata(ctx, manifest, from)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	toMeta, _, err := s.revisionMetadata(ctx, manifest, to)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	return RevisionDiff{From: from, To: to, Changes: diffMetadata(fromMeta, toMeta)}, nil
+}
+
+// Rollback makes revision n live again. The restored content is recorded as a
+// new revision so history stays append-only.
+func (s *Service) Rollback(ctx context.Context, adminCode, ref string, n int) (Revision, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return Revision{}, err
+	}
+	p, _, err := s.resolveOwned(ctx, ownerAddr, ref)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	liveKey := extractKeyFromMetadataURL(p.MetadataURL)
+	if !strings.HasPrefix(liveKey, fmt.Sprintf("users/%s/", ownerAddr.Hex())) {
+		return Revision{}, apperr.Post.ErrInvalidRequest
+	}
+	manifest, err := s.readManifest(ctx, liveKey)
+	if errors.Is(err, errObjectNotFound) {
+		return Revision{}, apperr.Post.ErrRevisionNotFound
+	}
+	if err != nil {
+		return Revision{}, err
+	}
+
+	restored, raw, err := s.revisionMetadata(ctx, manifest, n)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	rev, err := s.appendRevision(ctx, &manifest, raw, "rollback to revision "+strconv.Itoa(n))
+	if err != nil {
+		return Revision{}, err
+	}
+	if _, err := awss3.PutObject(ctx, s.cfg, liveKey, bytes.NewReader(raw), "application/json", ""); err != nil {
+		return Revision{}, apperr.Wrap(err, apperr.Post.ErrUploadMetadata.Code, apperr.Post.ErrUploadMetadata.Message, apperr.Post.ErrUploadMetadata.Status)
+	}
+	if err := s.writeManifest(ctx, manifest); err != nil {
+		return Revision{}, err
+	}
+	if err := s.saveRenderedContent(ctx, liveKey, restored); err != nil {
+		return Revision{}, err
+	}
+
+	s.cache.invalidate(ownerAddr, p.MetadataURL)
+	rev.Current = true
+	return rev, nil
+}
+
+func diffMetadata(from, to NftMetadata) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, FieldChange{Field: field, From: a, To: b})
+		}
+	}
+
+	add("name", from.Name, to.Name)
+	add("description", from.Description, to.Description)
+	add("image", from.Image, to.Image)
+	add("external_url", from.ExternalURL, to.ExternalURL)
+
+	fromAttrs := attributeMap(from.Attributes)
+	toAttrs := attributeMap(to.Attributes)
+	seen := make(map[string]bool, len(fromAttrs)+len(toAttrs))
+	for _, attr := range append(append([]NftAttribute{}, from.Attributes...), to.Attributes...) {
+		trait := strings.TrimSpace(attr.TraitType)
+		if seen[trait] {
+			continue
+		}
+		seen[trait] = true
+		add("attributes."+trait, fromAttrs[trait], toAttrs[trait])
+	}
+	return changes
+}
+
+func attributeMap(attrs []NftAttribute) map[string]string {
+	out := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		out[strings.TrimSpace(a.TraitType)] = attrString(a.Value)
+	}
+	return out
+}
+
+func (s *Service) readObject(ctx context.Context, key string) ([]byte, error) {
+	out, err := awss3.GetObject(ctx, s.cfg, key, "")
+	if err != nil {
+		var noKey *s3types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return nil, errObjectNotFound
+		}
+		return nil, fmt.Errorf("get object %s: %w", key, err)
+	}
+	defer out.Body.Close()
+
+	raw, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read object %s: %w", key, err)
+	}
+	return raw, nil
+}
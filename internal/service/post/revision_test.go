@@ -0,0 +1,42 @@
+package post
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffMetadataByTrait(t *testing.T) {
+	from := NftMetadata{
+		Name: "Old title",
+		Attributes: []NftAttribute{
+			{TraitType: "Slug", Value: json.RawMessage(`"hello"`)},
+			{TraitType: "Tags", Value: json.RawMessage(`"go"`)},
+		},
+	}
+	to := NftMetadata{
+		Name: "New title",
+		Attributes: []NftAttribute{
+			{TraitType: "Slug", Value: json.RawMessage(`"hello"`)},
+			{TraitType: "Summary", Value: json.RawMessage(`"added"`)},
+		},
+	}
+
+	changes := diffMetadata(from, to)
+	want := []FieldChange{
+		{Field: "name", From: "Old title", To: "New title"},
+		{Field: "attributes.Tags", From: "go"},
+		{Field: "attributes.Summary", To: "added"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("change %d: got %+v want %+v", i, changes[i], want[i])
+		}
+	}
+
+	if got := revisionKey("users/0xabc/posts/dev/lab/metadata-1.json", 3); got != "users/0xabc/posts/dev/lab/metadata-1.rev-3.json" {
+		t.Fatalf("unexpected revision key: %s", got)
+	}
+}
@@ -0,0 +1,320 @@
+package post
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"in-server/pkg/apperr"
+	appcrypto "in-server/pkg/crypto"
+	"in-server/pkg/firebase"
+)
+
+type JobStatus string
+
+const (
+	JobScheduled JobStatus = "scheduled"
+	JobRunning   JobStatus = "running"
+	JobPublished JobStatus = "published"
+	JobFailed    JobStatus = "failed"
+)
+
+// staleJobAfter releases jobs left in JobRunning by a process that died
+// mid-publish.
+const staleJobAfter = 10 * time.Minute
+
+type ScheduledJob struct {
+	ID            string      `json:"id"`
+	Owner         string      `json:"owner"`
+	Payload       NftMetadata `json:"payload"`
+	MetadataURL   string      `json:"metadataUrl,omitempty"`
+	PublishAt     string      `json:"publishAt"`
+	Status        JobStatus   `json:"status"`
+	Attempts      int         `json:"attempts"`
+	NextAttemptAt string      `json:"nextAttemptAt,omitempty"`
+	LastError     string      `json:"lastError,omitempty"`
+	PublishedURL  string      `json:"publishedUrl,omitempty"`
+	Notify        bool        `json:"notifySubscribers,omitempty"`
+	CreatedAt     string      `json:"createdAt"`
+	UpdatedAt     string      `json:"updatedAt"`
+
+	// AdminCode is stored encrypted with the auth hash and never returned.
+	AdminCode string `json:"adminCode,omitempty"`
+}
+
+func (j ScheduledJob) public() ScheduledJob {
+	j.AdminCode = ""
+	return j
+}
+
+// Schedule persists payload to be published at publishAt by the background
+// scheduler. With notify, subscribers are mailed once the post is published.
+func (s *Service) Schedule(ctx context.Context, adminCode string, payload NftMetadata, metadataURL string, publishAt time.Time, notify bool) (ScheduledJob, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return ScheduledJob{}, fmt.Errorf("firebase client is nil")
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+	if publishAt.IsZero() || !publishAt.After(time.Now()) {
+		return ScheduledJob{}, apperr.Post.ErrInvalidPublishAt
+	}
+
+	encrypted, err := appcrypto.Encrypt(strings.TrimSpace(adminCode), s.cfg.Auth.Hash)
+	if err != nil {
+		return ScheduledJob{}, fmt.Errorf("encrypt admin code: %w", err)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	job := ScheduledJob{
+		ID:            id,
+		Owner:         ownerAddr.Hex(),
+		Payload:       payload,
+		MetadataURL:   strings.TrimSpace(metadataURL),
+		PublishAt:     publishAt.UTC().Format(time.RFC3339),
+		Status:        JobScheduled,
+		NextAttemptAt: publishAt.UTC().Format(time.RFC3339),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		AdminCode:     encrypted,
+		Notify:        notify,
+	}
+	if err := s.writeJob(ctx, job); err != nil {
+		return ScheduledJob{}, err
+	}
+	return job.public(), nil
+}
+
+// ListScheduled returns the owner's jobs, optionally filtered by status.
+func (s *Service) ListScheduled(ctx context.Context, adminCode string, status JobStatus) ([]ScheduledJob, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.readJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ScheduledJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.Owner != ownerAddr.Hex() {
+			continue
+		}
+		if status != "" && job.Status != status {
+			continue
+		}
+		out = append(out, job.public())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return parsePublishedAt(out[i].PublishAt).Before(parsePublishedAt(out[j].PublishAt))
+	})
+	return out, nil
+}
+
+// CancelScheduled removes a job that has not been published yet.
+func (s *Service) CancelScheduled(ctx context.Context, adminCode, id string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ownerAddr, err := s.adminWallet(adminCode)
+	if err != nil {
+		return err
+	}
+
+	id = strings.TrimSpace(id)
+	if id == "" || toPathSegment(id) != id {
+		return apperr.Post.ErrJobNotFound
+	}
+	// Checking and deleting in one transaction keeps a job claimed by
+	// claimJob in the meantime from being published without its record.
+	_, err = firebase.Transaction(ctx, s.fb, s.jobPath(id), func(job *ScheduledJob, exists bool) (*ScheduledJob, error) {
+		if !exists || job == nil || job.Owner != ownerAddr.Hex() {
+			return nil, apperr.Post.ErrJobNotFound
+		}
+		if job.Status == JobRunning || job.Status == JobPublished {
+			return nil, apperr.Post.ErrJobNotCancelable
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ProcessScheduled publishes every job that is due. Failed attempts are
+// retried with exponential backoff until SCHEDULER_MAX_ATTEMPTS is reached.
+func (s *Service) ProcessScheduled(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.fb == nil {
+		return fmt.Errorf("firebase client is nil")
+	}
+
+	jobs, err := s.readJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		if !jobDue(job, now) {
+			continue
+		}
+		if err := s.runJob(ctx, job.ID); err != nil {
+			log.Printf("scheduled publish %s: %v", job.ID, err)
+		}
+	}
+	return nil
+}
+
+func jobDue(job ScheduledJob, now time.Time) bool {
+	switch job.Status {
+	case JobScheduled:
+		return !parsePublishedAt(job.NextAttemptAt).After(now)
+	case JobRunning:
+		return now.Sub(parsePublishedAt(job.UpdatedAt)) > staleJobAfter
+	default:
+		return false
+	}
+}
+
+// errJobTaken aborts a claim when the job is no longer due, usually because
+// another instance claimed it first.
+var errJobTaken = errors.New("scheduled post already claimed")
+
+// claimJob moves a due job to JobRunning in a transaction, so only one
+// server instance publishes it.
+func (s *Service) claimJob(ctx context.Context, id string) (ScheduledJob, error) {
+	return firebase.Transaction(ctx, s.fb, s.jobPath(id), func(job ScheduledJob, exists bool) (ScheduledJob, error) {
+		now := time.Now()
+		if !exists || !jobDue(job, now) {
+			return ScheduledJob{}, errJobTaken
+		}
+		job.Status = JobRunning
+		job.Attempts++
+		job.UpdatedAt = now.UTC().Format(time.RFC3339)
+		return job, nil
+	})
+}
+
+func (s *Service) runJob(ctx context.Context, id string) error {
+	job, err := s.claimJob(ctx, id)
+	if errors.Is(err, errJobTaken) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("claim scheduled post: %w", err)
+	}
+
+	adminCode, err := appcrypto.Decrypt(job.AdminCode, s.cfg.Auth.Hash)
+	if err == nil {
+		publishCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		job.PublishedURL, err = s.Publish(publishCtx, adminCode, job.Payload, job.MetadataURL)
+		cancel()
+	}
+
+	now := time.Now().UTC()
+	job.UpdatedAt = now.Format(time.RFC3339)
+	if err == nil {
+		job.Status = JobPublished
+		job.LastError = ""
+		job.NextAttemptAt = ""
+		if job.Notify {
+			s.notifyAfterPublish(ctx, adminCode, job.PublishedURL)
+		}
+		return s.writeJob(ctx, job)
+	}
+
+	job.LastError = err.Error()
+	maxAttempts := s.cfg.Scheduler.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if job.Attempts >= maxAttempts {
+		job.Status = JobFailed
+		job.NextAttemptAt = ""
+	} else {
+		job.Status = JobScheduled
+		backoff := time.Minute << (job.Attempts - 1)
+		job.NextAttemptAt = now.Add(backoff).Format(time.RFC3339)
+	}
+	if writeErr := s.writeJob(ctx, job); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+func (s *Service) jobsPath() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "scheduledPosts/" + env
+}
+
+func (s *Service) jobPath(id string) string {
+	return s.jobsPath() + "/" + id
+}
+
+func (s *Service) readJobs(ctx context.Context) (map[string]ScheduledJob, error) {
+	jobs, exists, err := firebase.Read[map[string]ScheduledJob](ctx, s.fb, s.jobsPath())
+	if err != nil {
+		return nil, fmt.Errorf("read scheduled posts: %w", err)
+	}
+	if !exists {
+		return map[string]ScheduledJob{}, nil
+	}
+	return jobs, nil
+}
+
+func (s *Service) readJob(ctx context.Context, id string) (ScheduledJob, error) {
+	id = strings.TrimSpace(id)
+	if id == "" || toPathSegment(id) != id {
+		return ScheduledJob{}, apperr.Post.ErrJobNotFound
+	}
+	job, exists, err := firebase.Read[ScheduledJob](ctx, s.fb, s.jobPath(id))
+	if err != nil {
+		return ScheduledJob{}, fmt.Errorf("read scheduled post: %w", err)
+	}
+	if !exists {
+		return ScheduledJob{}, apperr.Post.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (s *Service) writeJob(ctx context.Context, job ScheduledJob) error {
+	if err := firebase.Write(ctx, s.fb, s.jobPath(job.ID), job); err != nil {
+		return fmt.Errorf("write scheduled post: %w", err)
+	}
+	return nil
+}
+
+func newJobID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate job id: %w", err)
+	}
+	return fmt.Sprintf("job-%s-%s", strings.ToLower(timestampForKey()), hex.EncodeToString(buf)), nil
+}
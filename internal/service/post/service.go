@@ -19,6 +19,9 @@ import (
 	"in-server/pkg/config"
 	"in-server/pkg/eth"
 	"in-server/pkg/firebase"
+	"in-server/pkg/gemini"
+	googlemail "in-server/pkg/google"
+	"in-server/pkg/markdown"
 	"in-server/pkg/types"
 )
 
@@ -27,6 +30,9 @@ type Service struct {
 	eth        *eth.Client
 	httpClient *http.Client
 	fb         *firebase.Client
+	cache      *postCache
+	sendEmail  func(ctx context.Context, cfg config.Config, content googlemail.EmailContent) error
+	gemini     *gemini.Client
 }
 
 func New(ctx context.Context, cfg config.Config) (*Service, error) {
@@ -49,30 +55,41 @@ func New(ctx context.Context, cfg config.Config) (*Service, error) {
 		eth:        ethClient,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 		fb:         fbClient,
+		cache:      newPostCache(cfg.Cache.MetadataTTL, cfg.Cache.PostListTTL),
+		sendEmail:  googlemail.SendEmail,
+		gemini:     gemini.New(cfg),
 	}, nil
 }
 
 type Post struct {
-	ID                 string   `json:"id,omitempty"`
-	TokenID            string   `json:"tokenId,omitempty"`
-	Slug               string   `json:"slug,omitempty"`
-	Title              string   `json:"title,omitempty"`
-	Summary            string   `json:"summary,omitempty"`
-	Description        string   `json:"description,omitempty"`
-	Category           string   `json:"category,omitempty"`
-	LabName            string   `json:"labName,omitempty"`
-	LabSegment         string   `json:"labSegment,omitempty"`
-	Href               string   `json:"href,omitempty"`
-	PublishedAt        string   `json:"publishedAt,omitempty"`
-	ReadingTimeMinutes int      `json:"readingTimeMinutes,omitempty"`
-	ReadingTimeLabel   string   `json:"readingTimeLabel,omitempty"`
-	Tags               []string `json:"tags,omitempty"`
-	MetadataURL        string   `json:"metadataUrl,omitempty"`
-	Image              string   `json:"image,omitempty"`
-	ExternalURL        string   `json:"externalUrl,omitempty"`
-	Content            string   `json:"content,omitempty"`
-	RelatedLinks       []string `json:"relatedLinks,omitempty"`
-	StructuredData     string   `json:"structuredData,omitempty"`
+	ID                 string                 `json:"id,omitempty"`
+	TokenID            string                 `json:"tokenId,omitempty"`
+	Slug               string                 `json:"slug,omitempty"`
+	Title              string                 `json:"title,omitempty"`
+	Summary            string                 `json:"summary,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	Category           string                 `json:"category,omitempty"`
+	LabName            string                 `json:"labName,omitempty"`
+	LabSegment         string                 `json:"labSegment,omitempty"`
+	Href               string                 `json:"href,omitempty"`
+	PublishedAt        string                 `json:"publishedAt,omitempty"`
+	ReadingTimeMinutes int                    `json:"readingTimeMinutes,omitempty"`
+	ReadingTimeLabel   string                 `json:"readingTimeLabel,omitempty"`
+	Tags               []string               `json:"tags,omitempty"`
+	MetadataURL        string                 `json:"metadataUrl,omitempty"`
+	Image              string                 `json:"image,omitempty"`
+	ExternalURL        string                 `json:"externalUrl,omitempty"`
+	Content            string                 `json:"content,omitempty"`
+	ContentHTML        string                 `json:"contentHtml,omitempty"`
+	TOC                []markdown.Heading     `json:"toc,omitempty"`
+	RelatedLinks       []RelatedLink          `json:"relatedLinks,omitempty"`
+	StructuredData     string                 `json:"structuredData,omitempty"`
+	UpdatedAt          string                 `json:"updatedAt,omitempty"`
+	Owner              string                 `json:"owner,omitempty"`
+	Author             *AuthorProfile         `json:"author,omitempty"`
+	Reactions          map[ReactionKind]int64 `json:"reactions,omitempty"`
+	ViewCount          int64                  `json:"viewCount"`
+	Series             *SeriesNav             `json:"series,omitempty"`
 }
 
 type metadata struct {
@@ -81,6 +98,9 @@ type metadata struct {
 	Image       string      `json:"image"`
 	ExternalURL string      `json:"external_url"`
 	Attributes  []attribute `json:"attributes"`
+
+	// lastModified is the Last-Modified header of the metadata object.
+	lastModified time.Time
 }
 
 type attribute struct {
@@ -92,21 +112,29 @@ type attribute struct {
 func (s *Service) List() ([]Post, error) {
 	ctx := context.Background()
 
+	ownerAddr, err := s.ownerAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listByOwner(ctx, ownerAddr)
+}
+
+func (s *Service) ownerAddress() (common.Address, error) {
 	if s.eth == nil {
-		return nil, fmt.Errorf("eth client is nil")
+		return common.Address{}, fmt.Errorf("eth client is nil")
 	}
 
 	adminCode := strings.TrimSpace(s.cfg.Auth.AdminCode)
 	if adminCode == "" {
-		return nil, apperr.System.ErrMissingAuthAdminCode
+		return common.Address{}, apperr.System.ErrMissingAuthAdminCode
 	}
 
 	_, ownerAddr, err := s.eth.Wallet(adminCode)
 	if err != nil {
-		return nil, fmt.Errorf("owner address: %w", err)
+		return common.Address{}, fmt.Errorf("owner address: %w", err)
 	}
-
-	return s.listByOwner(ctx, ownerAddr)
+	return ownerAddr, nil
 }
 
 func (s *Service) fetchMetadata(ctx context.Context, url string) (metadata, error) {
@@ -115,10 +143,23 @@ func (s *Service) fetchMetadata(ctx context.Context, url string) (metadata, erro
 		return metadata{}, apperr.System.ErrInvalidMetadataURL
 	}
 
+	cached, hasCached, fresh := s.cache.getMetadata(url)
+	if fresh {
+		return cached.meta, nil
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return metadata{}, fmt.Errorf("build request: %w", err)
 	}
+	if hasCached {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
@@ -126,6 +167,11 @@ func (s *Service) fetchMetadata(ctx context.Context, url string) (metadata, erro
 	}
 	defer resp.Body.Close()
 
+	if hasCached && resp.StatusCode == http.StatusNotModified {
+		s.cache.revalidateMetadata(url)
+		return cached.meta, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return metadata{}, apperr.Wrap(fmt.Errorf("status %d", resp.StatusCode), apperr.System.ErrLoadMetadata.Code, "unexpected metadata status", apperr.System.ErrLoadMetadata.Status)
 	}
@@ -134,13 +180,25 @@ func (s *Service) fetchMetadata(ctx context.Context, url string) (metadata, erro
 	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
 		return metadata{}, fmt.Errorf("decode metadata: %w", err)
 	}
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		meta.lastModified = lm
+	}
+
+	s.cache.putMetadata(url, metadataEntry{
+		meta:         meta,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		fetchedAt:    time.Now(),
+	})
 	return meta, nil
 }
 
-func (s *Service) listByOwner(ctx context.Context, ownerAddr common.Address) ([]Post, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+type postRef struct {
+	Id  *big.Int
+	Uri string
+}
+
+func (s *Service) postRefs(ctx context.Context, ownerAddr common.Address) ([]postRef, error) {
 	if s.eth == nil {
 		return nil, fmt.Errorf("eth client is nil")
 	}
@@ -173,7 +231,33 @@ func (s *Service) listByOwner(ctx context.Context, ownerAddr common.Address) ([]
 		return nil, fmt.Errorf("unexpected call result type for getPosts")
 	}
 
-	raw := *rawPtr
+	refs := make([]postRef, len(*rawPtr))
+	for i, p := range *rawPtr {
+		refs[i] = postRef{Id: p.Id, Uri: p.Uri}
+	}
+	s.applyAliases(ctx, refs)
+	return refs, nil
+}
+
+func (s *Service) listByOwner(ctx context.Context, ownerAddr common.Address) ([]Post, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if cached, ok := s.cache.getList(ownerAddr); ok {
+		return cached, nil
+	}
+	return s.loadByOwner(ctx, ownerAddr)
+}
+
+// loadByOwner reads the owner's posts from PostStorage, bypassing the
+// listing cache, and refreshes the cache with the result.
+func (s *Service) loadByOwner(ctx context.Context, ownerAddr common.Address) ([]Post, error) {
+	raw, err := s.postRefs(ctx, ownerAddr)
+	if err != nil {
+		return nil, err
+	}
+
 	posts := make([]Post, len(raw))
 	eg, egctx := errgroup.WithContext(ctx)
 	for i, p := range raw {
@@ -185,6 +269,7 @@ func (s *Service) listByOwner(ctx context.Context, ownerAddr common.Address) ([]
 			}
 			tokenID := p.Id.String()
 			posts[i] = mapMetadataToPost(meta, tokenID, p.Uri)
+			posts[i].Owner = ownerAddr.Hex()
 			return nil
 		})
 	}
@@ -196,6 +281,7 @@ func (s *Service) listByOwner(ctx context.Context, ownerAddr common.Address) ([]
 		return parsePublishedAt(posts[i].PublishedAt).After(parsePublishedAt(posts[j].PublishedAt))
 	})
 
+	s.cache.putList(ownerAddr, posts)
 	return posts, nil
 }
 
@@ -203,7 +289,7 @@ func mapMetadataToPost(meta metadata, tokenID, metadataURL string) Post {
 	var slug, summary, category, labName, labSegment, href, publishedAt, readingLabel, content, structuredData string
 	var readingMinutes int
 	var tags []string
-	var related []string
+	var related []RelatedLink
 
 	title := strings.TrimSpace(meta.Name)
 	description := strings.TrimSpace(meta.Description)
@@ -236,6 +322,8 @@ func mapMetadataToPost(meta metadata, tokenID, metadataURL string) Post {
 			labName = val
 		case "StructuredData":
 			structuredData = val
+		case "RelatedLinks":
+			related = relatedLinksForPost(attr.Value, externalURL)
 		}
 	}
 
@@ -251,6 +339,11 @@ func mapMetadataToPost(meta metadata, tokenID, metadataURL string) Post {
 		href = pathFromURL(externalURL)
 	}
 
+	updatedAt := ""
+	if !meta.lastModified.IsZero() {
+		updatedAt = meta.lastModified.UTC().Format(time.RFC3339)
+	}
+
 	return Post{
 		ID:                 tokenID,
 		TokenID:            tokenID,
@@ -272,6 +365,7 @@ func mapMetadataToPost(meta metadata, tokenID, metadataURL string) Post {
 		Content:            content,
 		RelatedLinks:       related,
 		StructuredData:     structuredData,
+		UpdatedAt:          updatedAt,
 	}
 }
 
@@ -329,5 +423,3 @@ func parsePublishedAt(val string) time.Time {
 	}
 	return t
 }
-
-func (s *Service) Create(p Post) error { return nil }
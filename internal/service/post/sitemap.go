@@ -0,0 +1,181 @@
+package post
+
+import (
+	"context"
+	"encoding/xml"
+	"sort"
+	"strings"
+	"time"
+
+	"in-server/pkg/apperr"
+)
+
+// sitemapMaxURLs is the per-file limit from the sitemaps.org protocol.
+const sitemapMaxURLs = 50000
+
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type Sitemap struct {
+	URLs    []SitemapURL
+	perPage int
+}
+
+// Sitemap builds sitemap entries for every listed post plus one entry per
+// distinct lab segment. It reads the same cached listing as GET /posts.
+func (s *Service) Sitemap(ctx context.Context) (Sitemap, error) {
+	page, err := s.Query(ctx, ListQuery{})
+	if err != nil {
+		return Sitemap{}, err
+	}
+	return buildSitemap(s.siteURL(page.Items), page.Items), nil
+}
+
+func buildSitemap(siteURL string, posts []Post) Sitemap {
+	urls := make([]SitemapURL, 0, len(posts)+1)
+	labs := make(map[string]time.Time)
+	var latest time.Time
+
+	for _, p := range posts {
+		loc := absolutePostURL(siteURL, p)
+		if loc == "" {
+			continue
+		}
+		lastMod := postLastModified(p)
+		urls = append(urls, SitemapURL{Loc: loc, LastMod: lastMod})
+
+		if lastMod.After(latest) {
+			latest = lastMod
+		}
+		if p.LabSegment != "" {
+			if cur, ok := labs[p.LabSegment]; !ok || lastMod.After(cur) {
+				labs[p.LabSegment] = lastMod
+			}
+		}
+	}
+
+	if siteURL != "" {
+		labURLs := make([]SitemapURL, 0, len(labs)+1)
+		labURLs = append(labURLs, SitemapURL{Loc: siteURL + "/", LastMod: latest})
+		for seg, lastMod := range labs {
+			labURLs = append(labURLs, SitemapURL{Loc: siteURL + "/" + seg, LastMod: lastMod})
+		}
+		sort.Slice(labURLs[1:], func(i, j int) bool { return labURLs[i+1].Loc < labURLs[j+1].Loc })
+		urls = append(labURLs, urls...)
+	}
+
+	return Sitemap{URLs: urls, perPage: sitemapMaxURLs}
+}
+
+// postLastModified prefers the metadata object's Last-Modified time so that
+// metadata updates bump lastmod, falling back to PublishedAt.
+func postLastModified(p Post) time.Time {
+	published := parsePublishedAt(p.PublishedAt)
+	updated := parsePublishedAt(p.UpdatedAt)
+	if updated.After(published) {
+		return updated
+	}
+	return published
+}
+
+// Pages returns the number of sitemap files needed for all URLs.
+func (m Sitemap) Pages() int {
+	if len(m.URLs) == 0 {
+		return 1
+	}
+	return (len(m.URLs) + m.pageSize() - 1) / m.pageSize()
+}
+
+func (m Sitemap) pageSize() int {
+	if m.perPage <= 0 {
+		return sitemapMaxURLs
+	}
+	return m.perPage
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name         `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURLNode `xml:"url"`
+}
+
+type sitemapURLNode struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name           `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapIndexNode `xml:"sitemap"`
+}
+
+type sitemapIndexNode struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Page renders the 1-based page n as a urlset document.
+func (m Sitemap) Page(n int) ([]byte, error) {
+	if n < 1 || n > m.Pages() {
+		return nil, apperr.Post.ErrSitemapPageNotFound
+	}
+	start := (n - 1) * m.pageSize()
+	end := start + m.pageSize()
+	if end > len(m.URLs) {
+		end = len(m.URLs)
+	}
+
+	set := sitemapURLSet{URLs: make([]sitemapURLNode, 0, end-start)}
+	for _, u := range m.URLs[start:end] {
+		set.URLs = append(set.URLs, sitemapURLNode{Loc: u.Loc, LastMod: formatLastMod(u.LastMod)})
+	}
+	return marshalXML(set)
+}
+
+// Index renders a sitemap index whose entries point at pageURL(n).
+func (m Sitemap) Index(pageURL func(n int) string) ([]byte, error) {
+	idx := sitemapIndex{Sitemaps: make([]sitemapIndexNode, 0, m.Pages())}
+	for n := 1; n <= m.Pages(); n++ {
+		start := (n - 1) * m.pageSize()
+		end := start + m.pageSize()
+		if end > len(m.URLs) {
+			end = len(m.URLs)
+		}
+		var latest time.Time
+		for _, u := range m.URLs[start:end] {
+			if u.LastMod.After(latest) {
+				latest = u.LastMod
+			}
+		}
+		idx.Sitemaps = append(idx.Sitemaps, sitemapIndexNode{Loc: pageURL(n), LastMod: formatLastMod(latest)})
+	}
+	return marshalXML(idx)
+}
+
+// LastModified returns the newest lastmod across all URLs.
+func (m Sitemap) LastModified() time.Time {
+	var latest time.Time
+	for _, u := range m.URLs {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+	return latest
+}
+
+// Robots renders a robots.txt body that advertises sitemapURL.
+func Robots(sitemapURL string) string {
+	lines := []string{"User-agent: *", "Allow: /"}
+	if sitemapURL = strings.TrimSpace(sitemapURL); sitemapURL != "" {
+		lines = append(lines, "", "Sitemap: "+sitemapURL)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func formatLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
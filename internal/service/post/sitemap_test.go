@@ -0,0 +1,43 @@
+package post
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuildSitemapPagesAndLastMod(t *testing.T) {
+	posts := []Post{
+		{Href: "/dev-lab/a", LabSegment: "dev-lab", PublishedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-05-01T00:00:00Z"},
+		{Href: "/dev-lab/b", LabSegment: "dev-lab", PublishedAt: "2024-02-01T00:00:00Z"},
+		{ExternalURL: "https://other.example/life-lab/c", LabSegment: "life-lab", PublishedAt: "2024-03-01T00:00:00Z"},
+	}
+
+	m := buildSitemap("https://in.example", posts)
+	if len(m.URLs) != 6 {
+		t.Fatalf("expected root + 2 labs + 3 posts, got %d: %+v", len(m.URLs), m.URLs)
+	}
+	if m.URLs[1].Loc != "https://in.example/dev-lab" || m.URLs[1].LastMod.Format("2006-01-02") != "2024-05-01" {
+		t.Fatalf("unexpected lab entry: %+v", m.URLs[1])
+	}
+
+	page, err := m.Page(1)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if !strings.Contains(string(page), "<lastmod>2024-05-01T00:00:00Z</lastmod>") {
+		t.Fatalf("metadata update should drive lastmod:\n%s", page)
+	}
+
+	m.perPage = 4
+	if m.Pages() != 2 {
+		t.Fatalf("expected 2 pages, got %d", m.Pages())
+	}
+	idx, err := m.Index(func(n int) string { return "https://api.example/posts/sitemap.xml?page=" + strconv.Itoa(n) })
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	if strings.Count(string(idx), "<sitemap>") != 2 {
+		t.Fatalf("unexpected index:\n%s", idx)
+	}
+}
@@ -0,0 +1,191 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"in-server/pkg/apperr"
+	awss3 "in-server/pkg/aws/s3"
+	"in-server/pkg/firebase"
+	pkgtypes "in-server/pkg/types"
+)
+
+type TransferMode string
+
+const (
+	TransferCopy TransferMode = "copy"
+	TransferMove TransferMode = "move"
+)
+
+type TransferResult struct {
+	TokenID     string       `json:"tokenId"`
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	TxHash      string       `json:"txHash"`
+	Mode        TransferMode `json:"mode"`
+	MetadataURL string       `json:"metadataUrl"`
+}
+
+// metadataAlias points a token at metadata stored somewhere other than its
+// on-chain URI. PostStorage has no way to update a token URI, so transfers
+// record where the new owner's copy lives.
+type metadataAlias struct {
+	OriginalURL string `json:"originalUrl"`
+	URL         string `json:"url"`
+	Owner       string `json:"owner"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// Transfer moves a post token to another wallet with safeTransferFrom signed
+// by the current owner, then copies its metadata under the new owner's
+// users/<addr>/ prefix so later Publish updates pass the prefix check. With
+// TransferMove the old object is deleted once the copy exists.
+func (s *Service) Transfer(ctx context.Context, adminCode, ref string, to common.Address, mode TransferMode) (TransferResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.eth == nil {
+		return TransferResult{}, fmt.Errorf("eth client is nil")
+	}
+	if s.fb == nil {
+		return TransferResult{}, fmt.Errorf("firebase client is nil")
+	}
+
+	adminCode = strings.TrimSpace(adminCode)
+	if adminCode == "" {
+		return TransferResult{}, apperr.Post.ErrAdminCodeMissing
+	}
+	switch mode {
+	case "":
+		mode = TransferCopy
+	case TransferCopy, TransferMove:
+	default:
+		return TransferResult{}, apperr.Post.ErrInvalidRequest
+	}
+
+	pk, ownerAddr, err := s.eth.Wallet(adminCode)
+	if err != nil {
+		return TransferResult{}, apperr.Wrap(err, apperr.Post.ErrInvalidAdminCode.Code, apperr.Post.ErrInvalidAdminCode.Message, apperr.Post.ErrInvalidAdminCode.Status)
+	}
+	if to == (common.Address{}) || to == ownerAddr {
+		return TransferResult{}, apperr.Post.ErrInvalidRecipient
+	}
+
+	p, tokenID, err := s.resolveOwned(ctx, ownerAddr, ref)
+	if err != nil {
+		return TransferResult{}, err
+	}
+
+	oldKey := extractKeyFromMetadataURL(p.MetadataURL)
+	fromPrefix := fmt.Sprintf("users/%s/", ownerAddr.Hex())
+	if oldKey == "" || !strings.HasPrefix(oldKey, fromPrefix) {
+		return TransferResult{}, apperr.Post.ErrInvalidRequest
+	}
+	newKey := fmt.Sprintf("users/%s/", to.Hex()) + strings.TrimPrefix(oldKey, fromPrefix)
+
+	// Copy before transferring so a failed copy leaves the token untouched.
+	newURL, err := awss3.CopyObject(ctx, s.cfg, oldKey, newKey, "")
+	if err != nil {
+		return TransferResult{}, apperr.Wrap(err, apperr.Post.ErrTransferFailed.Code, "copy metadata", apperr.Post.ErrTransferFailed.Status)
+	}
+	if _, err := awss3.CopyObject(ctx, s.cfg, renderedKey(oldKey), renderedKey(newKey), ""); err != nil {
+		log.Printf("post transfer: copy rendered content for %s: %v", oldKey, err)
+	}
+
+	receipt, err := s.eth.ExecuteMetaTx(ctx, s.fb, pkgtypes.POSTSTORAGE, pk, "safeTransferFrom", ownerAddr, to, tokenID)
+	if err == nil && (receipt == nil || receipt.Status != gethtypes.ReceiptStatusSuccessful) {
+		err = fmt.Errorf("meta tx status %v", receiptStatus(receipt))
+	}
+	if err != nil {
+		if delErr := awss3.DeleteObject(ctx, s.cfg, newKey, ""); delErr != nil {
+			log.Printf("post transfer: clean up %s: %v", newKey, delErr)
+		}
+		return TransferResult{}, apperr.Wrap(err, apperr.Post.ErrTransferFailed.Code, apperr.Post.ErrTransferFailed.Message, apperr.Post.ErrTransferFailed.Status)
+	}
+
+	alias := metadataAlias{
+		OriginalURL: p.MetadataURL,
+		URL:         newURL,
+		Owner:       to.Hex(),
+		UpdatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := firebase.Write(ctx, s.fb, s.aliasPath(tokenID.String()), alias); err != nil {
+		// The token already moved; keep the old object so reads still work.
+		log.Printf("post transfer: record alias for token %s: %v", tokenID, err)
+		mode = TransferCopy
+	} else if mode == TransferMove {
+		for _, key := range []string{oldKey, renderedKey(oldKey)} {
+			if err := awss3.DeleteObject(ctx, s.cfg, key, ""); err != nil {
+				log.Printf("post transfer: delete %s: %v", key, err)
+			}
+		}
+	}
+
+	s.cache.invalidate(ownerAddr, p.MetadataURL)
+	s.cache.invalidate(to, newURL)
+	s.rememberPublisher(ctx, to)
+
+	return TransferResult{
+		TokenID:     tokenID.String(),
+		From:        ownerAddr.Hex(),
+		To:          to.Hex(),
+		TxHash:      receipt.TxHash.Hex(),
+		Mode:        mode,
+		MetadataURL: newURL,
+	}, nil
+}
+
+func (s *Service) aliasesPath() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "postAliases/" + env
+}
+
+func (s *Service) aliasPath(tokenID string) string {
+	return s.aliasesPath() + "/" + tokenID
+}
+
+// applyAliases swaps on-chain URIs for transferred copies. Lookup failures
+// fall back to the on-chain URI.
+func (s *Service) applyAliases(ctx context.Context, refs []postRef) {
+	if s.fb == nil || len(refs) == 0 {
+		return
+	}
+	aliases, exists, err := firebase.Read[map[string]metadataAlias](ctx, s.fb, s.aliasesPath())
+	if err != nil {
+		log.Printf("post aliases: %v", err)
+		return
+	}
+	if !exists {
+		return
+	}
+	for i, ref := range refs {
+		if a, ok := aliases[ref.Id.String()]; ok && a.OriginalURL == ref.Uri && a.URL != "" {
+			refs[i].Uri = a.URL
+		}
+	}
+}
+
+func (s *Service) aliasedURI(ctx context.Context, tokenID *big.Int, uri string) string {
+	if s.fb == nil {
+		return uri
+	}
+	a, exists, err := firebase.Read[metadataAlias](ctx, s.fb, s.aliasPath(tokenID.String()))
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("post alias %s: %v", tokenID, err)
+	}
+	if err != nil || !exists || a.OriginalURL != uri || a.URL == "" {
+		return uri
+	}
+	return a.URL
+}
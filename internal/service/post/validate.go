@@ -0,0 +1,268 @@
+package post
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"in-server/pkg/apperr"
+)
+
+const (
+	maxNameRunes           = 200
+	maxDescriptionRunes    = 1000
+	maxURLLength           = 2048
+	maxSlugLength          = 100
+	maxLabRunes            = 100
+	maxSummaryRunes        = 500
+	maxContentRunes        = 200_000
+	maxTags                = 20
+	maxTagRunes            = 40
+	maxReadingMinutes      = 600
+	maxStructuredDataBytes = 20_000
+)
+
+type traitKind int
+
+const (
+	traitString traitKind = iota
+	traitNumber
+	// traitLinks is a string or a JSON array; see parseRelatedLinks.
+	traitLinks
+)
+
+type traitRule struct {
+	kind     traitKind
+	required bool
+	check    func(raw json.RawMessage) string
+}
+
+// traitRules lists every attribute a post may carry. mapMetadataToPost and
+// enrichMetadata read these names; anything else is rejected.
+var traitRules = map[string]traitRule{
+	"Slug":               {kind: traitString, required: true, check: checkSlug},
+	"Lab":                {kind: traitString, required: true, check: checkLab},
+	"PublishedAt":        {kind: traitString, required: true, check: checkRFC3339},
+	"Summary":            {kind: traitString, check: maxRunes(maxSummaryRunes)},
+	"Content":            {kind: traitString, check: maxRunes(maxContentRunes)},
+	"Tags":               {kind: traitString, check: checkTags},
+	"ReadingTimeMinutes": {kind: traitNumber, check: checkReadingMinutes},
+	"StructuredData":     {kind: traitString, check: checkStructuredData},
+	"RelatedLinks":       {kind: traitLinks, check: checkRelatedLinks},
+}
+
+// ValidateMetadata checks payload before it is published and reports every
+// problem at once as apperr.Post.ErrInvalidMetadata with one FieldError per
+// field. Attribute fields are named "attributes.<trait_type>", or by index
+// when the trait type itself is wrong.
+func ValidateMetadata(payload NftMetadata) error {
+	var fields []apperr.FieldError
+	add := func(field, msg string) {
+		fields = append(fields, apperr.FieldError{Field: field, Message: msg})
+	}
+
+	if msg := checkText(payload.Name, maxNameRunes, true); msg != "" {
+		add("name", msg)
+	}
+	if msg := checkText(payload.Description, maxDescriptionRunes, true); msg != "" {
+		add("description", msg)
+	}
+	if msg := checkURL(payload.ExternalURL, true, "http", "https"); msg != "" {
+		add("external_url", msg)
+	}
+	if msg := checkURL(payload.Image, false, "http", "https", "ipfs"); msg != "" {
+		add("image", msg)
+	}
+
+	seen := make(map[string]bool, len(payload.Attributes))
+	for i, attr := range payload.Attributes {
+		trait := strings.TrimSpace(attr.TraitType)
+		rule, ok := traitRules[trait]
+		if !ok {
+			if trait == "" {
+				add(fmt.Sprintf("attributes[%d].trait_type", i), "is required")
+			} else {
+				add(fmt.Sprintf("attributes[%d].trait_type", i), fmt.Sprintf("unknown trait type %q", trait))
+			}
+			continue
+		}
+
+		field := "attributes." + trait
+		if seen[trait] {
+			add(field, "appears more than once")
+			continue
+		}
+		seen[trait] = true
+
+		if msg := checkTraitValue(rule, attr.Value); msg != "" {
+			add(field, msg)
+		}
+	}
+	for i, trait := range payload.Generated {
+		if !slices.Contains(derivedTraits, strings.TrimSpace(trait)) {
+			add(fmt.Sprintf("generated[%d]", i), fmt.Sprintf("%q is not a derived attribute", trait))
+		}
+	}
+	for _, trait := range []string{"Slug", "Lab", "PublishedAt"} {
+		if !seen[trait] {
+			add("attributes."+trait, "is required")
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return apperr.WithFields(apperr.Post.ErrInvalidMetadata, fields)
+}
+
+func checkTraitValue(rule traitRule, raw json.RawMessage) string {
+	raw = json.RawMessage(strings.TrimSpace(string(raw)))
+	if len(raw) == 0 || string(raw) == "null" {
+		return "value is required"
+	}
+
+	switch rule.kind {
+	case traitString:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "value must be a string"
+		}
+		if rule.required && strings.TrimSpace(s) == "" {
+			return "value is required"
+		}
+	case traitNumber:
+		var f float64
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return "value must be a number"
+		}
+	case traitLinks:
+		if raw[0] != '"' && raw[0] != '[' {
+			return "value must be a string or an array"
+		}
+	}
+	if rule.check != nil {
+		return rule.check(raw)
+	}
+	return ""
+}
+
+func checkText(s string, limit int, required bool) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		if required {
+			return "is required"
+		}
+		return ""
+	}
+	if utf8.RuneCountInString(s) > limit {
+		return fmt.Sprintf("must be at most %d characters", limit)
+	}
+	return ""
+}
+
+func checkURL(raw string, required bool, schemes ...string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		if required {
+			return "is required"
+		}
+		return ""
+	}
+	if len(raw) > maxURLLength {
+		return fmt.Sprintf("must be at most %d characters", maxURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "must be an absolute URL"
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return ""
+		}
+	}
+	return "scheme must be one of " + strings.Join(schemes, ", ")
+}
+
+func maxRunes(limit int) func(json.RawMessage) string {
+	return func(raw json.RawMessage) string {
+		return checkText(attrString(raw), limit, false)
+	}
+}
+
+func checkSlug(raw json.RawMessage) string {
+	slug := strings.TrimSpace(attrString(raw))
+	if len(slug) > maxSlugLength {
+		return fmt.Sprintf("must be at most %d characters", maxSlugLength)
+	}
+	if !slugPattern.MatchString(slug) {
+		return "must be lowercase letters, digits and single hyphens"
+	}
+	return ""
+}
+
+// checkLab requires a name that still yields a URL path segment, since the
+// lab becomes the first segment of the post's key and href.
+func checkLab(raw json.RawMessage) string {
+	if msg := maxRunes(maxLabRunes)(raw); msg != "" {
+		return msg
+	}
+	if toPathSegment(attrString(raw)) == "" {
+		return "must contain at least one ASCII letter or digit"
+	}
+	return ""
+}
+
+func checkRFC3339(raw json.RawMessage) string {
+	if _, err := time.Parse(time.RFC3339, strings.TrimSpace(attrString(raw))); err != nil {
+		return "must be an RFC3339 time such as 2024-05-01T09:00:00Z"
+	}
+	return ""
+}
+
+func checkTags(raw json.RawMessage) string {
+	tags := strings.Fields(attrString(raw))
+	if len(tags) > maxTags {
+		return fmt.Sprintf("must have at most %d tags", maxTags)
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > maxTagRunes {
+			return fmt.Sprintf("tag %q must be at most %d characters", tag, maxTagRunes)
+		}
+	}
+	return ""
+}
+
+func checkReadingMinutes(raw json.RawMessage) string {
+	var f float64
+	_ = json.Unmarshal(raw, &f)
+	if f != float64(int(f)) || f < 1 || f > maxReadingMinutes {
+		return fmt.Sprintf("must be a whole number between 1 and %d", maxReadingMinutes)
+	}
+	return ""
+}
+
+func checkStructuredData(raw json.RawMessage) string {
+	s := strings.TrimSpace(attrString(raw))
+	if s == "" {
+		return ""
+	}
+	if len(s) > maxStructuredDataBytes {
+		return fmt.Sprintf("must be at most %d bytes", maxStructuredDataBytes)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		return "must be a JSON-LD object encoded as a string"
+	}
+	return ""
+}
+
+func checkRelatedLinks(raw json.RawMessage) string {
+	if _, err := parseRelatedLinks(raw); err != nil {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package post
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"in-server/pkg/apperr"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	str := func(s string) json.RawMessage { return json.RawMessage(mustJSONQuote(s)) }
+	valid := NftMetadata{
+		Name:        "Go 서버 만들기",
+		Description: "Building a server in Go",
+		Image:       "ipfs://bafy/cover.png",
+		ExternalURL: "https://in.example/dev-lab/go-server",
+		Attributes: []NftAttribute{
+			{TraitType: "Slug", Value: str("go-server")},
+			{TraitType: "Lab", Value: str("Dev Lab")},
+			{TraitType: "PublishedAt", Value: str("2024-05-01T09:00:00+09:00")},
+			{TraitType: "Tags", Value: str("go server")},
+			{TraitType: "ReadingTimeMinutes", Value: json.RawMessage("7"), DisplayType: "number"},
+			{TraitType: "RelatedLinks", Value: json.RawMessage(`["https://go.dev/doc"]`)},
+		},
+	}
+	if err := ValidateMetadata(valid); err != nil {
+		t.Fatalf("valid metadata: %v", err)
+	}
+
+	invalid := NftMetadata{
+		Description: "Building a server in Go",
+		Image:       "ftp://cdn/cover.png",
+		ExternalURL: "/dev-lab/go-server",
+		Attributes: []NftAttribute{
+			{TraitType: "Slug", Value: str("Go Server")},
+			{TraitType: "Lab", Value: str("개발")},
+			{TraitType: "PublishedAt", Value: str("2024-05-01")},
+			{TraitType: "ReadingTimeMinutes", Value: str("7")},
+			{TraitType: "Tags", Value: str("a")},
+			{TraitType: "Tags", Value: str("b")},
+			{TraitType: "Mood", Value: str("happy")},
+		},
+	}
+	err := ValidateMetadata(invalid)
+	if !errors.Is(err, apperr.Post.ErrInvalidMetadata) {
+		t.Fatalf("err = %v", err)
+	}
+	var appErr *apperr.Error
+	errors.As(err, &appErr)
+
+	got := make(map[string]string, len(appErr.Fields))
+	for _, f := range appErr.Fields {
+		got[f.Field] = f.Message
+	}
+	for _, field := range []string{
+		"name", "image", "external_url",
+		"attributes.Slug", "attributes.Lab", "attributes.PublishedAt",
+		"attributes.ReadingTimeMinutes", "attributes.Tags", "attributes[6].trait_type",
+	} {
+		if got[field] == "" {
+			t.Errorf("missing error for %s; got %v", field, got)
+		}
+	}
+	if len(got) != 9 {
+		t.Errorf("fields = %v", got)
+	}
+
+	err = ValidateMetadata(NftMetadata{Name: "x", Description: "y", ExternalURL: "https://in.example/a"})
+	if !errors.As(err, &appErr) || len(appErr.Fields) != 3 {
+		t.Fatalf("missing required attributes: %v", err)
+	}
+}
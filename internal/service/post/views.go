@@ -0,0 +1,387 @@
+package post
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/firebase"
+	"in-server/pkg/types"
+)
+
+const (
+	DefaultPopularDays  = 7
+	MaxPopularDays      = 90
+	DefaultPopularLimit = 10
+
+	// VisitorStorage numbers days since the Unix epoch in UTC.
+	secondsPerDay = 24 * 60 * 60
+)
+
+type DailyViews struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+}
+
+type PopularPost struct {
+	Post
+	Views int64        `json:"views"`
+	Daily []DailyViews `json:"daily"`
+}
+
+// dayVisits is one day of VisitorStorage data: parallel visitor hashes and
+// visited URLs.
+type dayVisits struct {
+	hashes []common.Hash
+	urls   []string
+}
+
+// AggregateViews folds completed VisitorStorage days into per-post daily
+// counts. Each visitor hash counts once per post per day. Days are
+// processed in order after the stored checkpoint; the first run backfills
+// VIEWS_BACKFILL_DAYS. Returns the number of days aggregated.
+func (s *Service) AggregateViews(ctx context.Context) (int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if s.eth == nil {
+		return 0, fmt.Errorf("eth client is nil")
+	}
+	if s.fb == nil {
+		return 0, fmt.Errorf("firebase client is nil")
+	}
+
+	ownerAddr, err := s.ownerAddress()
+	if err != nil {
+		return 0, err
+	}
+	contract, _, err := s.eth.Contract(types.VISITORSTORAGE)
+	if err != nil {
+		return 0, fmt.Errorf("bind visitor storage: %w", err)
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	var today uint64
+	out := []any{&today}
+	if err := contract.Call(callOpts, &out, "currentDayId"); err != nil {
+		return 0, fmt.Errorf("call currentDayId: %w", err)
+	}
+
+	checkpoint, exists, err := firebase.Read[uint64](ctx, s.fb, s.viewsCheckpointPath())
+	if err != nil {
+		return 0, fmt.Errorf("read views checkpoint: %w", err)
+	}
+	start := checkpoint + 1
+	if !exists {
+		backfill := uint64(max(s.cfg.Views.BackfillDays, 1))
+		start = 0
+		if today > backfill {
+			start = today - backfill
+		}
+	}
+	if start >= today {
+		return 0, nil
+	}
+
+	posts, err := s.viewablePosts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	index := viewIndex(posts)
+
+	batch := uint64(max(s.cfg.Views.BatchDays, 1))
+	processed := 0
+	for from := start; from < today; from += batch {
+		dayIDs := make([]uint64, 0, batch)
+		for day := from; day < today && day < from+batch; day++ {
+			dayIDs = append(dayIDs, day)
+		}
+
+		visits, err := s.exportVisits(callOpts, contract, ownerAddr, dayIDs)
+		if err != nil {
+			return processed, err
+		}
+		for i, day := range dayIDs {
+			for tokenID, views := range countViews(visits[i], index) {
+				path := s.viewsPostPath(tokenID) + "/" + strconv.FormatUint(day, 10)
+				if err := firebase.Write(ctx, s.fb, path, views); err != nil {
+					return processed, fmt.Errorf("write post views: %w", err)
+				}
+			}
+		}
+
+		last := dayIDs[len(dayIDs)-1]
+		if err := firebase.Write(ctx, s.fb, s.viewsCheckpointPath(), last); err != nil {
+			return processed, fmt.Errorf("write views checkpoint: %w", err)
+		}
+		processed += len(dayIDs)
+	}
+	return processed, nil
+}
+
+// visitorExport receives VisitorStorage.exportData's two outputs.
+type visitorExport struct {
+	HashesPerDay [][][32]byte
+	UrlsPerDay   [][]string
+}
+
+// exportVisits reads dayIDs with exportData. Busy days can exceed the node's
+// eth_call gas cap, so on failure each day is read with hashedVisitorsOf and
+// visitUrlAt instead.
+func (s *Service) exportVisits(opts *bind.CallOpts, contract *bind.BoundContract, owner common.Address, dayIDs []uint64) ([]dayVisits, error) {
+	var export visitorExport
+	out := []any{&export}
+	err := contract.Call(opts, &out, "exportData", owner, dayIDs)
+	if err == nil && len(export.HashesPerDay) == len(dayIDs) && len(export.UrlsPerDay) == len(dayIDs) {
+		visits := make([]dayVisits, len(dayIDs))
+		for i := range dayIDs {
+			visits[i].urls = export.UrlsPerDay[i]
+			visits[i].hashes = make([]common.Hash, len(export.HashesPerDay[i]))
+			for j, h := range export.HashesPerDay[i] {
+				visits[i].hashes[j] = h
+			}
+		}
+		return visits, nil
+	}
+	if err != nil {
+		log.Printf("post views: exportData %v: %v; reading days one by one", dayIDs, err)
+	}
+
+	visits := make([]dayVisits, len(dayIDs))
+	for i, day := range dayIDs {
+		var hashes [][32]byte
+		out := []any{&hashes}
+		if err := contract.Call(opts, &out, "hashedVisitorsOf", owner, day); err != nil {
+			return nil, fmt.Errorf("call hashedVisitorsOf: %w", err)
+		}
+		visits[i].hashes = make([]common.Hash, len(hashes))
+		visits[i].urls = make([]string, len(hashes))
+		for j, h := range hashes {
+			visits[i].hashes[j] = h
+			var u string
+			out := []any{&u}
+			if err := contract.Call(opts, &out, "visitUrlAt", owner, day, new(big.Int).SetInt64(int64(j))); err != nil {
+				return nil, fmt.Errorf("call visitUrlAt: %w", err)
+			}
+			visits[i].urls[j] = u
+		}
+	}
+	return visits, nil
+}
+
+// countViews maps a day's visited URLs to token IDs and counts distinct
+// visitors per post.
+func countViews(day dayVisits, index map[string]string) map[string]int64 {
+	seen := make(map[string]map[common.Hash]bool)
+	for i, raw := range day.urls {
+		tokenID, ok := index[normalizeViewPath(raw)]
+		if !ok {
+			continue
+		}
+		var hash common.Hash
+		if i < len(day.hashes) {
+			hash = day.hashes[i]
+		}
+		if seen[tokenID] == nil {
+			seen[tokenID] = make(map[common.Hash]bool)
+		}
+		seen[tokenID][hash] = true
+	}
+
+	counts := make(map[string]int64, len(seen))
+	for tokenID, visitors := range seen {
+		counts[tokenID] = int64(len(visitors))
+	}
+	return counts
+}
+
+// viewIndex maps each post's normalized Href to its token ID.
+func viewIndex(posts []Post) map[string]string {
+	index := make(map[string]string, len(posts))
+	for _, p := range posts {
+		if p.Href == "" || p.TokenID == "" {
+			continue
+		}
+		index[normalizeViewPath(p.Href)] = p.TokenID
+	}
+	return index
+}
+
+// normalizeViewPath reduces a visited URL or Href to a lowercase path without
+// query, fragment or trailing slash.
+func normalizeViewPath(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if u, err := url.Parse(raw); err == nil {
+		raw = u.Path
+	}
+	raw = strings.ToLower(strings.TrimRight(raw, "/"))
+	if !strings.HasPrefix(raw, "/") {
+		raw = "/" + raw
+	}
+	return raw
+}
+
+// Popular ranks posts by views over the last days completed days.
+func (s *Service) Popular(ctx context.Context, days, limit int) ([]PopularPost, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if days <= 0 {
+		days = DefaultPopularDays
+	}
+	if days > MaxPopularDays {
+		return nil, apperr.Post.ErrInvalidQuery
+	}
+	if limit <= 0 {
+		limit = DefaultPopularLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	checkpoint, exists, err := firebase.Read[uint64](ctx, s.fb, s.viewsCheckpointPath())
+	if err != nil {
+		return nil, fmt.Errorf("read views checkpoint: %w", err)
+	}
+	if !exists {
+		return []PopularPost{}, nil
+	}
+	views, err := s.readViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.viewablePosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := rankPopular(posts, views, checkpoint, days)
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	for i := range out {
+		out[i].ViewCount = sumViews(views[out[i].TokenID])
+	}
+	return out, nil
+}
+
+// viewablePosts lists the posts of every known owner. VisitorStorage logs
+// visits under the site owner whoever wrote the post, so views are matched
+// against all of them by href.
+func (s *Service) viewablePosts(ctx context.Context) ([]Post, error) {
+	owners, err := s.knownOwners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var all []Post
+	for _, owner := range owners {
+		posts, err := s.listByOwner(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, posts...)
+	}
+	return all, nil
+}
+
+func rankPopular(posts []Post, views map[string]map[string]int64, lastDay uint64, days int) []PopularPost {
+	firstDay := uint64(0)
+	if lastDay+1 > uint64(days) {
+		firstDay = lastDay + 1 - uint64(days)
+	}
+
+	out := make([]PopularPost, 0, len(posts))
+	for _, p := range posts {
+		series := views[p.TokenID]
+		entry := PopularPost{Post: p, Daily: make([]DailyViews, 0, days)}
+		for day := firstDay; day <= lastDay; day++ {
+			n := series[strconv.FormatUint(day, 10)]
+			entry.Views += n
+			entry.Daily = append(entry.Daily, DailyViews{Date: dayDate(day), Views: n})
+		}
+		if entry.Views > 0 {
+			out = append(out, entry)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Views != out[j].Views {
+			return out[i].Views > out[j].Views
+		}
+		return parsePublishedAt(out[i].PublishedAt).After(parsePublishedAt(out[j].PublishedAt))
+	})
+	return out
+}
+
+// attachViews sets ViewCount on posts. Like reactions, counts are
+// decoration and read failures are only logged.
+func (s *Service) attachViews(ctx context.Context, posts []Post) {
+	if len(posts) == 0 || s.fb == nil {
+		return
+	}
+
+	var (
+		views map[string]map[string]int64
+		err   error
+	)
+	if len(posts) == 1 {
+		var series map[string]int64
+		series, _, err = firebase.Read[map[string]int64](ctx, s.fb, s.viewsPostPath(posts[0].TokenID))
+		views = map[string]map[string]int64{posts[0].TokenID: series}
+	} else {
+		views, err = s.readViews(ctx)
+	}
+	if err != nil {
+		log.Printf("post views: %v", err)
+		return
+	}
+	for i := range posts {
+		posts[i].ViewCount = sumViews(views[posts[i].TokenID])
+	}
+}
+
+func (s *Service) readViews(ctx context.Context) (map[string]map[string]int64, error) {
+	views, _, err := firebase.Read[map[string]map[string]int64](ctx, s.fb, s.viewsRoot()+"/posts")
+	if err != nil {
+		return nil, fmt.Errorf("read post views: %w", err)
+	}
+	return views, nil
+}
+
+func sumViews(series map[string]int64) int64 {
+	var total int64
+	for _, n := range series {
+		total += n
+	}
+	return total
+}
+
+func dayDate(day uint64) string {
+	return time.Unix(int64(day)*secondsPerDay, 0).UTC().Format("2006-01-02")
+}
+
+func (s *Service) viewsRoot() string {
+	env := strings.TrimSpace(s.cfg.Env)
+	if env == "" {
+		env = "development"
+	}
+	return "postViews/" + env
+}
+
+func (s *Service) viewsPostPath(tokenID string) string {
+	return s.viewsRoot() + "/posts/" + tokenID
+}
+
+func (s *Service) viewsCheckpointPath() string {
+	return s.viewsRoot() + "/checkpoint"
+}
@@ -0,0 +1,93 @@
+package post
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"in-server/pkg/abis"
+	"in-server/pkg/types"
+)
+
+func TestNormalizeViewPath(t *testing.T) {
+	cases := map[string]string{
+		"https://in-labs.xyz/Dev-Lab/Go-Server/?utm=x#top": "/dev-lab/go-server",
+		"/dev-lab/go-server":  "/dev-lab/go-server",
+		"dev-lab/go-server/":  "/dev-lab/go-server",
+		"":                    "/",
+		"https://in-labs.xyz": "/",
+	}
+	for in, want := range cases {
+		if got := normalizeViewPath(in); got != want {
+			t.Errorf("normalizeViewPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCountViewsDedupesVisitorsPerPost(t *testing.T) {
+	index := viewIndex([]Post{
+		{TokenID: "1", Href: "/dev-lab/go-server"},
+		{TokenID: "2", Href: "/dev-lab/gin"},
+	})
+	a, b := common.HexToHash("0xa"), common.HexToHash("0xb")
+	day := dayVisits{
+		hashes: []common.Hash{a, a, b, b, a},
+		urls:   []string{"/dev-lab/go-server", "https://site/dev-lab/go-server/", "/dev-lab/go-server", "/dev-lab/gin", "/about"},
+	}
+
+	got := countViews(day, index)
+	if len(got) != 2 || got["1"] != 2 || got["2"] != 1 {
+		t.Fatalf("countViews = %v", got)
+	}
+}
+
+func TestRankPopularWindow(t *testing.T) {
+	posts := []Post{{TokenID: "1"}, {TokenID: "2"}, {TokenID: "3"}}
+	views := map[string]map[string]int64{
+		"1": {"100": 50, "108": 1},
+		"2": {"109": 3, "110": 2},
+		"3": {"110": 4},
+	}
+
+	got := rankPopular(posts, views, 110, 3)
+	if len(got) != 3 {
+		t.Fatalf("got %d posts: %+v", len(got), got)
+	}
+	if got[0].TokenID != "2" || got[0].Views != 5 || got[1].TokenID != "3" || got[2].Views != 1 {
+		t.Fatalf("ranking = %+v", got)
+	}
+	if len(got[0].Daily) != 3 || got[0].Daily[2].Date != dayDate(110) || got[0].Daily[2].Views != 2 {
+		t.Fatalf("daily series = %+v", got[0].Daily)
+	}
+	if dayDate(0) != "1970-01-01" {
+		t.Fatalf("dayDate(0) = %s", dayDate(0))
+	}
+}
+
+func TestVisitorExportUnpacksABI(t *testing.T) {
+	artifacts, err := abis.Get("development")
+	if err != nil {
+		t.Fatalf("load abis: %v", err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifacts[types.VISITORSTORAGE].ABI)))
+	if err != nil {
+		t.Fatalf("parse abi: %v", err)
+	}
+
+	hashes := [][][32]byte{{common.HexToHash("0xa")}, {}}
+	urls := [][]string{{"/dev-lab/go-server"}, {}}
+	packed, err := parsed.Methods["exportData"].Outputs.Pack(hashes, urls)
+	if err != nil {
+		t.Fatalf("pack outputs: %v", err)
+	}
+
+	var export visitorExport
+	if err := parsed.UnpackIntoInterface(&export, "exportData", packed); err != nil {
+		t.Fatalf("unpack exportData: %v", err)
+	}
+	if len(export.UrlsPerDay) != 2 || export.UrlsPerDay[0][0] != "/dev-lab/go-server" || export.HashesPerDay[0][0] != common.HexToHash("0xa") {
+		t.Fatalf("export = %+v", export)
+	}
+}
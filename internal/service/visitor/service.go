@@ -74,7 +74,7 @@ func (s *Service) Visit(ip, url string) error {
 		return fmt.Errorf("bind visitor storage: %w", err)
 	}
 
-	ipHash := common.HexToHash("0x" + crypto.SHA256(strings.TrimSpace(ip)))
+	ipHash := common.HexToHash("0x" + crypto.HashIP(ip))
 	targetURL := strings.TrimSpace(url)
 	if targetURL == "" {
 		targetURL = "/"
@@ -120,7 +120,7 @@ func (s *Service) HasVisited(ip string) (bool, error) {
 		return false, fmt.Errorf("unexpected currentDayId result type")
 	}
 
-	ipHash := "0x" + crypto.SHA256(strings.TrimSpace(ip))
+	ipHash := "0x" + crypto.HashIP(ip)
 	hash := common.HexToHash(ipHash)
 
 	visitedOut := []any{new(bool)}
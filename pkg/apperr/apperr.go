@@ -11,6 +11,14 @@ type Error struct {
 	Message string
 	Status  int
 	Err     error
+	// Fields lists per-field problems for validation errors and is written
+	// to the response body next to code and message.
+	Fields []FieldError
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func (e *Error) Error() string {
@@ -33,6 +41,12 @@ func Wrap(err error, code, message string, status int) *Error {
 	return &Error{Code: code, Message: message, Status: status, Err: err}
 }
 
+// WithFields returns a copy of base carrying fields. The copy still matches
+// base with errors.Is.
+func WithFields(base *Error, fields []FieldError) *Error {
+	return &Error{Code: base.Code, Message: base.Message, Status: base.Status, Fields: fields}
+}
+
 func (e *Error) Is(target error) bool {
 	var t *Error
 	if !errors.As(target, &t) {
@@ -46,12 +60,14 @@ var System = struct {
 	ErrLoadMetadata         *Error
 	ErrMissingAuthHash      *Error
 	ErrMissingAuthAdminCode *Error
+	ErrInvalidAdminCode     *Error
 	ErrNotImplemented       *Error
 }{
 	ErrInvalidMetadataURL:   New("INVALID_METADATA_URL", "invalid post metadata url", http.StatusBadRequest),
 	ErrLoadMetadata:         New("FAILED_LOAD_METADATA", "failed to load metadata", http.StatusInternalServerError),
 	ErrMissingAuthHash:      New("ADMIN_AUTH_CODE_HASH_NOT_FOUND", "auth hash (salt) is empty", http.StatusInternalServerError),
 	ErrMissingAuthAdminCode: New("ADMIN_AUTH_CODE_NOT_FOUND", "auth admin code is empty", http.StatusInternalServerError),
+	ErrInvalidAdminCode:     New("INVALID_ADMIN_CODE", "invalid admin code", http.StatusBadRequest),
 	ErrNotImplemented:       New("NOT_IMPLEMENTED", "not implemented", http.StatusNotImplemented),
 }
 
@@ -94,27 +110,83 @@ var Subscriber = struct {
 }
 
 var Post = struct {
-	ErrInvalidBody      *Error
-	ErrAdminCodeMissing *Error
-	ErrInvalidAdminCode *Error
-	ErrDuplicatePost    *Error
-	ErrInvalidRequest   *Error
-	ErrS3BucketMissing  *Error
-	ErrUploadMetadata   *Error
-	ErrPublishFailed    *Error
-	ErrNoImageFile      *Error
-	ErrInvalidUpload    *Error
+	ErrInvalidBody          *Error
+	ErrAdminCodeMissing     *Error
+	ErrInvalidAdminCode     *Error
+	ErrDuplicatePost        *Error
+	ErrInvalidRequest       *Error
+	ErrS3BucketMissing      *Error
+	ErrUploadMetadata       *Error
+	ErrPublishFailed        *Error
+	ErrNoImageFile          *Error
+	ErrInvalidUpload        *Error
+	ErrPostNotFound         *Error
+	ErrNotPostOwner         *Error
+	ErrDeleteFailed         *Error
+	ErrInvalidQuery         *Error
+	ErrDraftNotFound        *Error
+	ErrDraftStorage         *Error
+	ErrInvalidPublishAt     *Error
+	ErrJobNotFound          *Error
+	ErrJobNotCancelable     *Error
+	ErrRevisionNotFound     *Error
+	ErrInvalidRelatedLinks  *Error
+	ErrInvalidRecipient     *Error
+	ErrTransferFailed       *Error
+	ErrInvalidOwner         *Error
+	ErrNotAuthor            *Error
+	ErrInvalidAuthor        *Error
+	ErrNewsletterNotFound   *Error
+	ErrInvalidReaction      *Error
+	ErrReactionStorage      *Error
+	ErrInvalidCollection    *Error
+	ErrCollectionNotFound   *Error
+	ErrDuplicateCollection  *Error
+	ErrNotCollectionOwner   *Error
+	ErrInvalidPreview       *Error
+	ErrPreviewExpired       *Error
+	ErrPreviewSecretMissing *Error
+	ErrInvalidMetadata      *Error
+	ErrSitemapPageNotFound  *Error
 }{
-	ErrInvalidBody:      New("INVALID_BODY", "invalid request body", http.StatusBadRequest),
-	ErrAdminCodeMissing: New("ADMIN_CODE_MISSING", "admin code is required", http.StatusBadRequest),
-	ErrInvalidAdminCode: New("INVALID_ADMIN_CODE", "invalid admin code", http.StatusBadRequest),
-	ErrDuplicatePost:    New("DUPLICATE_POST", "a post with the same slug already exists", http.StatusConflict),
-	ErrInvalidRequest:   New("INVALID_REQUEST", "invalid request", http.StatusBadRequest),
-	ErrS3BucketMissing:  New("AWS_S3_BUCKET_MISSING", "aws s3 bucket is empty", http.StatusInternalServerError),
-	ErrUploadMetadata:   New("FAILED_UPLOAD_METADATA", "failed to upload metadata", http.StatusInternalServerError),
-	ErrPublishFailed:    New("FAILED_PUBLISH_POST", "failed to publish post", http.StatusInternalServerError),
-	ErrNoImageFile:      New("NO_IMAGE_FILE", "image file is missing", http.StatusBadRequest),
-	ErrInvalidUpload:    New("INVALID_UPLOAD", "failed to upload media", http.StatusInternalServerError),
+	ErrInvalidBody:          New("INVALID_BODY", "invalid request body", http.StatusBadRequest),
+	ErrAdminCodeMissing:     New("ADMIN_CODE_MISSING", "admin code is required", http.StatusBadRequest),
+	ErrInvalidAdminCode:     System.ErrInvalidAdminCode,
+	ErrDuplicatePost:        New("DUPLICATE_POST", "a post with the same slug already exists", http.StatusConflict),
+	ErrInvalidRequest:       New("INVALID_REQUEST", "invalid request", http.StatusBadRequest),
+	ErrS3BucketMissing:      New("AWS_S3_BUCKET_MISSING", "aws s3 bucket is empty", http.StatusInternalServerError),
+	ErrUploadMetadata:       New("FAILED_UPLOAD_METADATA", "failed to upload metadata", http.StatusInternalServerError),
+	ErrPublishFailed:        New("FAILED_PUBLISH_POST", "failed to publish post", http.StatusInternalServerError),
+	ErrNoImageFile:          New("NO_IMAGE_FILE", "image file is missing", http.StatusBadRequest),
+	ErrInvalidUpload:        New("INVALID_UPLOAD", "failed to upload media", http.StatusInternalServerError),
+	ErrPostNotFound:         New("POST_NOT_FOUND", "post not found", http.StatusNotFound),
+	ErrNotPostOwner:         New("NOT_POST_OWNER", "post is not owned by this wallet", http.StatusForbidden),
+	ErrDeleteFailed:         New("FAILED_DELETE_POST", "failed to delete post", http.StatusInternalServerError),
+	ErrInvalidQuery:         New("INVALID_QUERY", "invalid query parameters", http.StatusBadRequest),
+	ErrDraftNotFound:        New("DRAFT_NOT_FOUND", "draft not found", http.StatusNotFound),
+	ErrDraftStorage:         New("FAILED_DRAFT_STORAGE", "failed to access draft storage", http.StatusInternalServerError),
+	ErrInvalidPublishAt:     New("INVALID_PUBLISH_AT", "publishAt must be a future RFC3339 time", http.StatusBadRequest),
+	ErrJobNotFound:          New("SCHEDULED_POST_NOT_FOUND", "scheduled post not found", http.StatusNotFound),
+	ErrJobNotCancelable:     New("SCHEDULED_POST_NOT_CANCELABLE", "scheduled post can no longer be canceled", http.StatusConflict),
+	ErrRevisionNotFound:     New("REVISION_NOT_FOUND", "revision not found", http.StatusNotFound),
+	ErrInvalidRelatedLinks:  New("INVALID_RELATED_LINKS", "related links are invalid", http.StatusUnprocessableEntity),
+	ErrInvalidRecipient:     New("INVALID_RECIPIENT", "recipient must be a different wallet address", http.StatusBadRequest),
+	ErrTransferFailed:       New("FAILED_TRANSFER_POST", "failed to transfer post", http.StatusInternalServerError),
+	ErrInvalidOwner:         New("INVALID_OWNER", "owner must be a wallet address", http.StatusBadRequest),
+	ErrNotAuthor:            New("NOT_AUTHOR", "admin code does not belong to this author", http.StatusForbidden),
+	ErrInvalidAuthor:        New("INVALID_AUTHOR_PROFILE", "invalid author profile", http.StatusBadRequest),
+	ErrNewsletterNotFound:   New("NEWSLETTER_NOT_FOUND", "newsletter not found", http.StatusNotFound),
+	ErrInvalidReaction:      New("INVALID_REACTION", "reaction kind must be like, love, clap, insightful or fire", http.StatusBadRequest),
+	ErrReactionStorage:      New("FAILED_REACTION_STORAGE", "failed to access reaction storage", http.StatusInternalServerError),
+	ErrInvalidCollection:    New("INVALID_COLLECTION", "invalid collection", http.StatusBadRequest),
+	ErrCollectionNotFound:   New("COLLECTION_NOT_FOUND", "collection not found", http.StatusNotFound),
+	ErrDuplicateCollection:  New("DUPLICATE_COLLECTION", "a collection with the same id already exists", http.StatusConflict),
+	ErrNotCollectionOwner:   New("NOT_COLLECTION_OWNER", "collection belongs to another wallet", http.StatusForbidden),
+	ErrInvalidPreview:       New("INVALID_PREVIEW", "invalid preview link", http.StatusBadRequest),
+	ErrPreviewExpired:       New("PREVIEW_EXPIRED", "preview link has expired", http.StatusGone),
+	ErrPreviewSecretMissing: New("PREVIEW_SECRET_MISSING", "preview signing secret is not configured", http.StatusInternalServerError),
+	ErrInvalidMetadata:      New("INVALID_METADATA", "metadata failed validation", http.StatusUnprocessableEntity),
+	ErrSitemapPageNotFound:  New("SITEMAP_PAGE_NOT_FOUND", "sitemap page not found", http.StatusNotFound),
 }
 
 var Email = struct {
@@ -130,3 +202,49 @@ var Email = struct {
 	ErrVerifyPinCode:      New("FAILED_TO_VERIFY_PIN_CODE", "failed to verify pin code", http.StatusInternalServerError),
 	ErrInvalidEmail:       New("INVALID_EMAIL", "invalid email address", http.StatusBadRequest),
 }
+
+var Event = struct {
+	ErrIndexerDisabled *Error
+	ErrInvalidQuery    *Error
+	ErrLoadEvents      *Error
+}{
+	ErrIndexerDisabled: New("EVENT_INDEXER_DISABLED", "event indexer is disabled", http.StatusServiceUnavailable),
+	ErrInvalidQuery:    New("INVALID_QUERY", "invalid query parameters", http.StatusBadRequest),
+	ErrLoadEvents:      New("FAILED_LOAD_EVENTS", "failed to load events", http.StatusInternalServerError),
+}
+
+var Comment = struct {
+	ErrInvalidBody     *Error
+	ErrInvalidComment  *Error
+	ErrInvalidPost     *Error
+	ErrCommentNotFound *Error
+	ErrInvalidParent   *Error
+	ErrRateLimited     *Error
+	ErrInvalidPinCode  *Error
+	ErrTooManyAttempts *Error
+	ErrAlreadyVerified *Error
+	ErrInvalidAction   *Error
+	ErrStorage         *Error
+}{
+	ErrInvalidBody:     New("INVALID_BODY", "invalid request body", http.StatusBadRequest),
+	ErrInvalidComment:  New("INVALID_COMMENT", "name, email and body are required and must fit the length limits", http.StatusBadRequest),
+	ErrInvalidPost:     New("INVALID_POST_ID", "comments are keyed by post token id", http.StatusBadRequest),
+	ErrCommentNotFound: New("COMMENT_NOT_FOUND", "comment not found", http.StatusNotFound),
+	ErrInvalidParent:   New("INVALID_PARENT_COMMENT", "parent comment cannot be replied to", http.StatusBadRequest),
+	ErrRateLimited:     New("COMMENT_RATE_LIMITED", "too many comments, try again later", http.StatusTooManyRequests),
+	ErrInvalidPinCode:  New("INVALID_PIN_CODE", "pin code does not match", http.StatusBadRequest),
+	ErrTooManyAttempts: New("TOO_MANY_PIN_ATTEMPTS", "too many failed pin attempts, comment discarded", http.StatusTooManyRequests),
+	ErrAlreadyVerified: New("COMMENT_ALREADY_VERIFIED", "comment email is already verified", http.StatusConflict),
+	ErrInvalidAction:   New("INVALID_MODERATION_ACTION", "action must be approve, hide or delete", http.StatusBadRequest),
+	ErrStorage:         New("FAILED_COMMENT_STORAGE", "failed to access comment storage", http.StatusInternalServerError),
+}
+
+var Gemini = struct {
+	ErrAPIKeyMissing   *Error
+	ErrRequestFailed   *Error
+	ErrInvalidResponse *Error
+}{
+	ErrAPIKeyMissing:   New("GEMINI_API_KEY_MISSING", "gemini api key is empty", http.StatusServiceUnavailable),
+	ErrRequestFailed:   New("FAILED_GEMINI_REQUEST", "gemini request failed", http.StatusBadGateway),
+	ErrInvalidResponse: New("INVALID_GEMINI_RESPONSE", "unexpected gemini response", http.StatusBadGateway),
+}
@@ -120,6 +120,26 @@ func DeleteObject(ctx context.Context, cfg config.Config, key string, bucketOver
 	return err
 }
 
+func CopyObject(ctx context.Context, cfg config.Config, srcKey, dstKey string, bucketOverride string) (string, error) {
+	res, err := Resolve(ctx, cfg, bucketOverride)
+	if err != nil {
+		return "", err
+	}
+
+	srcKey = strings.TrimPrefix(strings.TrimSpace(srcKey), "/")
+	dstKey = strings.TrimPrefix(strings.TrimSpace(dstKey), "/")
+
+	if _, err := res.Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(res.Bucket),
+		CopySource: aws.String(res.Bucket + "/" + encodePath(srcKey)),
+		Key:        aws.String(dstKey),
+	}); err != nil {
+		return "", err
+	}
+
+	return BuildObjectURL(res.Bucket, res.Region, dstKey), nil
+}
+
 func ListObjects(ctx context.Context, cfg config.Config, prefix string, bucketOverride string) ([]s3types.Object, error) {
 	res, err := Resolve(ctx, cfg, bucketOverride)
 	if err != nil {
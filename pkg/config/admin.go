@@ -0,0 +1,22 @@
+package config
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"in-server/pkg/apperr"
+)
+
+// AuthorizeAdmin checks adminCode against AUTH_ADMIN_CODE in constant time.
+// Operator endpoints use it; a code that merely derives a wallet is not
+// enough for them.
+func (c Config) AuthorizeAdmin(adminCode string) error {
+	want := strings.TrimSpace(c.Auth.AdminCode)
+	if want == "" {
+		return apperr.System.ErrMissingAuthAdminCode
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(adminCode)), []byte(want)) != 1 {
+		return apperr.System.ErrInvalidAdminCode
+	}
+	return nil
+}
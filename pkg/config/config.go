@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -12,6 +13,12 @@ import (
 type Config struct {
 	Env  string `envconfig:"ENV" default:"development"`
 	Port string `envconfig:"PORT" default:":4000"`
+	// TrustedProxies lists the load balancer addresses or CIDRs whose
+	// X-Forwarded-For header is honoured. The default trusts the private
+	// ranges a load balancer inside the VPC connects from; production refuses
+	// to start with an empty list, since every caller would then share the
+	// proxy's address.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES" default:"10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,127.0.0.0/8"`
 
 	Auth struct {
 		Hash string `envconfig:"AUTH_HASH"`
@@ -57,6 +64,55 @@ type Config struct {
 		GmailSender         string `envconfig:"GOOGLE_GMAIL_SENDER"`
 		RedirectURIEndpoint string `envconfig:"GOOGLE_REDIRECT_URI_ENDPOINT"`
 		GeminiAPIKey        string `envconfig:"GOOGLE_GEMINI_API_KEY"`
+		GeminiEndpoint      string `envconfig:"GOOGLE_GEMINI_ENDPOINT" default:"https://generativelanguage.googleapis.com/v1beta"`
+		GeminiModel         string `envconfig:"GOOGLE_GEMINI_MODEL" default:"gemini-1.5-flash"`
+	}
+
+	Site struct {
+		URL         string `envconfig:"SITE_URL"`
+		Title       string `envconfig:"SITE_TITLE" default:"IN Labs"`
+		Description string `envconfig:"SITE_DESCRIPTION" default:"IN Labs posts"`
+		Language    string `envconfig:"SITE_LANGUAGE" default:"ko"`
+	}
+
+	Scheduler struct {
+		Interval    time.Duration `envconfig:"SCHEDULER_INTERVAL" default:"30s"`
+		MaxAttempts int           `envconfig:"SCHEDULER_MAX_ATTEMPTS" default:"3"`
+	}
+
+	Cache struct {
+		MetadataTTL time.Duration `envconfig:"CACHE_METADATA_TTL" default:"10m"`
+		PostListTTL time.Duration `envconfig:"CACHE_POST_LIST_TTL" default:"1m"`
+	}
+
+	Newsletter struct {
+		Interval    time.Duration `envconfig:"NEWSLETTER_INTERVAL" default:"1m"`
+		BatchSize   int           `envconfig:"NEWSLETTER_BATCH_SIZE" default:"20"`
+		SendDelay   time.Duration `envconfig:"NEWSLETTER_SEND_DELAY" default:"1s"`
+		MaxAttempts int           `envconfig:"NEWSLETTER_MAX_ATTEMPTS" default:"3"`
+	}
+
+	Comments struct {
+		MaxLength   int           `envconfig:"COMMENTS_MAX_LENGTH" default:"2000"`
+		MaxDepth    int           `envconfig:"COMMENTS_MAX_DEPTH" default:"3"`
+		RateLimit   int           `envconfig:"COMMENTS_RATE_LIMIT" default:"5"`
+		RateWindow  time.Duration `envconfig:"COMMENTS_RATE_WINDOW" default:"10m"`
+		MaxAttempts int           `envconfig:"COMMENTS_MAX_PIN_ATTEMPTS" default:"5"`
+	}
+
+	Views struct {
+		Interval     time.Duration `envconfig:"VIEWS_INTERVAL" default:"1h"`
+		BackfillDays int           `envconfig:"VIEWS_BACKFILL_DAYS" default:"30"`
+		BatchDays    int           `envconfig:"VIEWS_BATCH_DAYS" default:"7"`
+	}
+
+	Indexer struct {
+		Enabled       bool          `envconfig:"INDEXER_ENABLED" default:"false"`
+		DataDir       string        `envconfig:"INDEXER_DATA_DIR" default:"data/events"`
+		StartBlock    uint64        `envconfig:"INDEXER_START_BLOCK"`
+		BatchSize     uint64        `envconfig:"INDEXER_BATCH_SIZE" default:"2000"`
+		Confirmations uint64        `envconfig:"INDEXER_CONFIRMATIONS" default:"2"`
+		Interval      time.Duration `envconfig:"INDEXER_INTERVAL" default:"15s"`
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+	"strings"
 )
 
 const (
@@ -88,6 +89,18 @@ func SHA256(data string) string {
 	return hex.EncodeToString(sum[:])
 }
 
+// HashIP is the unsalted SHA256 of the trimmed address, as VisitorStorage
+// records it on chain. Anything stored off chain should use SaltedHashIP,
+// since an unsalted IPv4 hash is reversed by enumerating the address space.
+func HashIP(ip string) string {
+	return SHA256(strings.TrimSpace(ip))
+}
+
+// SaltedHashIP hashes the trimmed address with salt, normally AUTH_HASH.
+func SaltedHashIP(salt, ip string) string {
+	return SHA256(salt + ":" + strings.TrimSpace(ip))
+}
+
 func evpBytesToKey(password, salt []byte, keyLen, ivLen int) ([]byte, []byte) {
 	return evpBytesToKeySHA256(password, salt, keyLen, ivLen)
 }
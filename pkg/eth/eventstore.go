@@ -0,0 +1,183 @@
+package eth
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FileStore keeps events as JSON lines plus a checkpoint file in a local
+// directory. Everything is also held in memory for queries.
+type FileStore struct {
+	mu         sync.RWMutex
+	dir        string
+	events     []Event
+	seen       map[string]bool
+	checkpoint uint64
+	hasCheck   bool
+}
+
+var (
+	fileStoresMu sync.Mutex
+	fileStores   = map[string]*FileStore{}
+)
+
+// OpenFileStore opens or creates the store in dir. Stores are shared per
+// directory so services rebuilt on config reload append to the same files.
+func OpenFileStore(dir string) (*FileStore, error) {
+	abs, err := filepath.Abs(strings.TrimSpace(dir))
+	if err != nil {
+		return nil, fmt.Errorf("event store dir: %w", err)
+	}
+
+	fileStoresMu.Lock()
+	defer fileStoresMu.Unlock()
+	if st, ok := fileStores[abs]; ok {
+		return st, nil
+	}
+
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("create event store dir: %w", err)
+	}
+	st := &FileStore{dir: abs, seen: make(map[string]bool)}
+	if err := st.load(); err != nil {
+		return nil, err
+	}
+	fileStores[abs] = st
+	return st, nil
+}
+
+func (s *FileStore) eventsPath() string     { return filepath.Join(s.dir, "events.jsonl") }
+func (s *FileStore) checkpointPath() string { return filepath.Join(s.dir, "checkpoint") }
+
+func (s *FileStore) load() error {
+	raw, err := os.ReadFile(s.checkpointPath())
+	switch {
+	case err == nil:
+		n, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse checkpoint: %w", err)
+		}
+		s.checkpoint, s.hasCheck = n, true
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("read checkpoint: %w", err)
+	}
+
+	f, err := os.Open(s.eventsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open events: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			// A torn last line from a crash mid-write is dropped; the
+			// checkpoint was not advanced past it so it will be re-indexed.
+			continue
+		}
+		if s.seen[ev.key()] {
+			continue
+		}
+		s.seen[ev.key()] = true
+		s.events = append(s.events, ev)
+	}
+	return sc.Err()
+}
+
+func (s *FileStore) Checkpoint(ctx context.Context) (uint64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoint, s.hasCheck, nil
+}
+
+// Save appends events not stored yet, then advances the checkpoint. Events
+// are written first so a crash in between only causes a re-index of the
+// batch, which the dedupe on load absorbs.
+func (s *FileStore) Save(ctx context.Context, events []Event, checkpoint uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var fresh []Event
+	for _, ev := range events {
+		if !s.seen[ev.key()] {
+			fresh = append(fresh, ev)
+		}
+	}
+
+	if len(fresh) > 0 {
+		f, err := os.OpenFile(s.eventsPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("open events: %w", err)
+		}
+		w := bufio.NewWriter(f)
+		enc := json.NewEncoder(w)
+		for _, ev := range fresh {
+			if err := enc.Encode(ev); err != nil {
+				f.Close()
+				return fmt.Errorf("write event: %w", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			f.Close()
+			return fmt.Errorf("flush events: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("close events: %w", err)
+		}
+		for _, ev := range fresh {
+			s.seen[ev.key()] = true
+			s.events = append(s.events, ev)
+		}
+	}
+
+	tmp := s.checkpointPath() + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(checkpoint, 10)), 0o644); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, s.checkpointPath()); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	s.checkpoint, s.hasCheck = checkpoint, true
+	return nil
+}
+
+// Events returns matching events, newest first.
+func (s *FileStore) Events(ctx context.Context, filter EventFilter) ([]Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Event, 0)
+	for _, ev := range s.events {
+		if filter.match(ev) {
+			out = append(out, ev)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].BlockNumber != out[j].BlockNumber {
+			return out[i].BlockNumber > out[j].BlockNumber
+		}
+		return out[i].LogIndex > out[j].LogIndex
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
@@ -0,0 +1,270 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"in-server/pkg/types"
+)
+
+// Event is a decoded contract log. Fields holds the event arguments by ABI
+// name; addresses, hashes and bytes are hex strings and integers are decimal
+// strings so events round-trip through JSON unchanged.
+type Event struct {
+	Contract    types.ContractName `json:"contract"`
+	Address     string             `json:"address"`
+	Name        string             `json:"name"`
+	BlockNumber uint64             `json:"blockNumber"`
+	TxHash      string             `json:"txHash"`
+	LogIndex    uint               `json:"logIndex"`
+	Fields      map[string]any     `json:"fields"`
+}
+
+func (e Event) key() string {
+	return fmt.Sprintf("%s:%d", e.TxHash, e.LogIndex)
+}
+
+type EventFilter struct {
+	Contract  types.ContractName
+	Name      string
+	FromBlock uint64
+	ToBlock   uint64
+	Limit     int
+}
+
+func (f EventFilter) match(e Event) bool {
+	if f.Contract != "" && !strings.EqualFold(string(f.Contract), string(e.Contract)) {
+		return false
+	}
+	if f.Name != "" && f.Name != e.Name {
+		return false
+	}
+	if f.FromBlock > 0 && e.BlockNumber < f.FromBlock {
+		return false
+	}
+	if f.ToBlock > 0 && e.BlockNumber > f.ToBlock {
+		return false
+	}
+	return true
+}
+
+// EventStore persists indexed events together with the last fully indexed
+// block, so a restarted indexer resumes where it stopped.
+type EventStore interface {
+	Checkpoint(ctx context.Context) (uint64, bool, error)
+	Save(ctx context.Context, events []Event, checkpoint uint64) error
+	Events(ctx context.Context, filter EventFilter) ([]Event, error)
+}
+
+// LogReader is the part of an RPC client the indexer needs. Both
+// *ethclient.Client and the simulated backend client satisfy it; see
+// pkg/eth/simulated for the test against the latter.
+type LogReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]gethtypes.Log, error)
+}
+
+type IndexedContract struct {
+	Name    types.ContractName
+	Address common.Address
+	ABI     abi.ABI
+}
+
+type IndexerOptions struct {
+	StartBlock    uint64
+	BatchSize     uint64
+	Confirmations uint64
+}
+
+type Indexer struct {
+	reader    LogReader
+	store     EventStore
+	contracts map[common.Address]IndexedContract
+	opts      IndexerOptions
+}
+
+func NewIndexer(reader LogReader, store EventStore, contracts []IndexedContract, opts IndexerOptions) (*Indexer, error) {
+	if reader == nil {
+		return nil, fmt.Errorf("log reader is nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("event store is nil")
+	}
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("no contracts to index")
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 2000
+	}
+
+	byAddr := make(map[common.Address]IndexedContract, len(contracts))
+	for _, c := range contracts {
+		byAddr[c.Address] = c
+	}
+	return &Indexer{reader: reader, store: store, contracts: byAddr, opts: opts}, nil
+}
+
+// NewIndexer builds an indexer for the named contracts using the embedded
+// ABIs and addresses for the configured env.
+func (c *Client) NewIndexer(store EventStore, opts IndexerOptions, names ...types.ContractName) (*Indexer, error) {
+	if c == nil || c.rpc == nil {
+		return nil, fmt.Errorf("client is nil")
+	}
+	contracts := make([]IndexedContract, 0, len(names))
+	for _, name := range names {
+		addr, parsed, err := c.contractABI(name)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, IndexedContract{Name: name, Address: addr, ABI: parsed})
+	}
+	return NewIndexer(c.rpc, store, contracts, opts)
+}
+
+// Sync indexes logs from the block after the checkpoint up to the latest
+// block minus Confirmations, saving after every batch. It returns the number
+// of events stored.
+func (ix *Indexer) Sync(ctx context.Context) (int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	head, err := ix.reader.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("block number: %w", err)
+	}
+	if head < ix.opts.Confirmations {
+		return 0, nil
+	}
+	head -= ix.opts.Confirmations
+
+	from := ix.opts.StartBlock
+	checkpoint, ok, err := ix.store.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if ok && checkpoint+1 > from {
+		from = checkpoint + 1
+	}
+
+	addrs := make([]common.Address, 0, len(ix.contracts))
+	for addr := range ix.contracts {
+		addrs = append(addrs, addr)
+	}
+
+	total := 0
+	for from <= head {
+		to := min(from+ix.opts.BatchSize-1, head)
+		logs, err := ix.reader.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: addrs,
+		})
+		if err != nil {
+			return total, fmt.Errorf("filter logs %d-%d: %w", from, to, err)
+		}
+
+		events := make([]Event, 0, len(logs))
+		for _, lg := range logs {
+			if lg.Removed {
+				continue
+			}
+			ev, ok, err := ix.decode(lg)
+			if err != nil {
+				return total, err
+			}
+			if ok {
+				events = append(events, ev)
+			}
+		}
+		if err := ix.store.Save(ctx, events, to); err != nil {
+			return total, err
+		}
+		total += len(events)
+		from = to + 1
+	}
+	return total, nil
+}
+
+func (ix *Indexer) decode(lg gethtypes.Log) (Event, bool, error) {
+	contract, ok := ix.contracts[lg.Address]
+	if !ok || len(lg.Topics) == 0 {
+		return Event{}, false, nil
+	}
+	ev, err := contract.ABI.EventByID(lg.Topics[0])
+	if err != nil {
+		// Events missing from the ABI (e.g. emitted by a newer implementation)
+		// are skipped rather than stalling the indexer.
+		return Event{}, false, nil
+	}
+
+	fields := make(map[string]any, len(ev.Inputs))
+	if len(lg.Data) > 0 {
+		if err := contract.ABI.UnpackIntoMap(fields, ev.Name, lg.Data); err != nil {
+			return Event{}, false, fmt.Errorf("unpack %s.%s: %w", contract.Name, ev.Name, err)
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(fields, indexed, lg.Topics[1:]); err != nil {
+		return Event{}, false, fmt.Errorf("parse topics %s.%s: %w", contract.Name, ev.Name, err)
+	}
+	for k, v := range fields {
+		fields[k] = eventValue(v)
+	}
+
+	return Event{
+		Contract:    contract.Name,
+		Address:     lg.Address.Hex(),
+		Name:        ev.Name,
+		BlockNumber: lg.BlockNumber,
+		TxHash:      lg.TxHash.Hex(),
+		LogIndex:    lg.Index,
+		Fields:      fields,
+	}, true, nil
+}
+
+func eventValue(v any) any {
+	switch t := v.(type) {
+	case common.Address:
+		return t.Hex()
+	case common.Hash:
+		return t.Hex()
+	case *big.Int:
+		return t.String()
+	case []byte:
+		return hexutil.Encode(t)
+	case [32]byte:
+		return hexutil.Encode(t[:])
+	case string, bool:
+		return t
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%d", rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("%d", rv.Uint())
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = eventValue(rv.Index(i).Interface())
+		}
+		return out
+	}
+	return v
+}
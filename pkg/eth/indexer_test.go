@@ -0,0 +1,93 @@
+package eth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"in-server/pkg/abis"
+	"in-server/pkg/types"
+)
+
+type fakeLogs struct {
+	head uint64
+	logs []gethtypes.Log
+}
+
+func (f *fakeLogs) BlockNumber(ctx context.Context) (uint64, error) { return f.head, nil }
+
+func (f *fakeLogs) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	var out []gethtypes.Log
+	for _, lg := range f.logs {
+		if lg.BlockNumber >= q.FromBlock.Uint64() && lg.BlockNumber <= q.ToBlock.Uint64() {
+			out = append(out, lg)
+		}
+	}
+	return out, nil
+}
+
+func TestIndexerDecodesAndResumes(t *testing.T) {
+	artifacts, err := abis.Get("development")
+	if err != nil {
+		t.Fatalf("load abis: %v", err)
+	}
+	visitorABI, err := abi.JSON(strings.NewReader(string(artifacts[types.VISITORSTORAGE].ABI)))
+	if err != nil {
+		t.Fatalf("parse abi: %v", err)
+	}
+	recorded := visitorABI.Events["HashedVisitorRecorded"]
+	contract := common.HexToAddress("0x1234")
+	owner := common.HexToAddress("0xabcd")
+
+	visit := func(block uint64, url string) gethtypes.Log {
+		data, err := recorded.Inputs.NonIndexed().Pack([32]byte{1}, url)
+		if err != nil {
+			t.Fatalf("pack: %v", err)
+		}
+		return gethtypes.Log{
+			Address:     contract,
+			Topics:      []common.Hash{recorded.ID, common.BytesToHash(owner.Bytes()), common.BigToHash(common.Big2)},
+			Data:        data,
+			BlockNumber: block,
+			TxHash:      common.BigToHash(common.Big1),
+			Index:       uint(block),
+		}
+	}
+
+	reader := &fakeLogs{head: 5, logs: []gethtypes.Log{visit(3, "/a")}}
+	store, err := OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ix, err := NewIndexer(reader, store, []IndexedContract{{Name: types.VISITORSTORAGE, Address: contract, ABI: visitorABI}}, IndexerOptions{BatchSize: 2, Confirmations: 1})
+	if err != nil {
+		t.Fatalf("new indexer: %v", err)
+	}
+
+	ctx := context.Background()
+	if n, err := ix.Sync(ctx); err != nil || n != 1 {
+		t.Fatalf("first sync: n=%d err=%v", n, err)
+	}
+	if cp, _, _ := store.Checkpoint(ctx); cp != 4 {
+		t.Fatalf("checkpoint should trail head by confirmations, got %d", cp)
+	}
+
+	reader.head = 8
+	reader.logs = append(reader.logs, visit(6, "/b"))
+	if n, err := ix.Sync(ctx); err != nil || n != 1 {
+		t.Fatalf("second sync: n=%d err=%v", n, err)
+	}
+
+	events, _ := store.Events(ctx, EventFilter{Contract: types.VISITORSTORAGE})
+	if len(events) != 2 || events[0].Fields["url"] != "/b" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[0].Fields["owner"] != owner.Hex() || events[0].Fields["dayId"] != "2" {
+		t.Fatalf("indexed fields not decoded: %+v", events[0].Fields)
+	}
+}
@@ -0,0 +1,108 @@
+//go:build simulated
+
+// Package simulated runs the event indexer against go-ethereum's simulated
+// backend. It is its own module so the backend's dependencies stay out of the
+// server's go.mod. The backend pulls in github.com/fjl/memsize, which newer
+// Go toolchains refuse to link by default. Run from this directory with:
+//
+//	go test -tags simulated -ldflags=-checklinkname=0 ./...
+package simulated_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+
+	"in-server/pkg/abis"
+	"in-server/pkg/eth"
+	"in-server/pkg/types"
+)
+
+// emitterCode deploys a contract that emits LOG2 for every call, taking
+// topic0 and topic1 from the first 64 bytes of calldata and the rest as data.
+const emitterCode = "0x6014600c60003960146000f3" + "366000600037602051600051604036036040a200"
+
+func TestIndexerFollowsSimulatedChain(t *testing.T) {
+	key, _ := gethcrypto.GenerateKey()
+	from := gethcrypto.PubkeyToAddress(key.PublicKey)
+	backend := simulated.NewBackend(gethtypes.GenesisAlloc{
+		from: {Balance: new(big.Int).Mul(big.NewInt(1e18), big.NewInt(100))},
+	})
+	defer backend.Close()
+	client := backend.Client()
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatalf("transactor: %v", err)
+	}
+	addr, _, emitter, err := bind.DeployContract(opts, abi.ABI{}, hexutil.MustDecode(emitterCode), client)
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	backend.Commit()
+
+	artifacts, err := abis.Get("development")
+	if err != nil {
+		t.Fatalf("load abis: %v", err)
+	}
+	subscriberABI, err := abi.JSON(strings.NewReader(string(artifacts[types.SUBSCRIBERSTORAGE].ABI)))
+	if err != nil {
+		t.Fatalf("parse abi: %v", err)
+	}
+	added := subscriberABI.Events["SubscriberEmailAdded"]
+
+	emit := func(account common.Address, email string) {
+		data, err := added.Inputs.NonIndexed().Pack(email)
+		if err != nil {
+			t.Fatalf("pack: %v", err)
+		}
+		calldata := append(append(added.ID.Bytes(), common.BytesToHash(account.Bytes()).Bytes()...), data...)
+		if _, err := emitter.RawTransact(opts, calldata); err != nil {
+			t.Fatalf("emit: %v", err)
+		}
+		backend.Commit()
+	}
+
+	store, err := eth.OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ix, err := eth.NewIndexer(client, store, []eth.IndexedContract{{Name: types.SUBSCRIBERSTORAGE, Address: addr, ABI: subscriberABI}}, eth.IndexerOptions{BatchSize: 1})
+	if err != nil {
+		t.Fatalf("new indexer: %v", err)
+	}
+
+	ctx := context.Background()
+	emit(from, "first@example.com")
+	if n, err := ix.Sync(ctx); err != nil || n != 1 {
+		t.Fatalf("first sync: n=%d err=%v", n, err)
+	}
+
+	emit(from, "second@example.com")
+	if n, err := ix.Sync(ctx); err != nil || n != 1 {
+		t.Fatalf("second sync should resume from checkpoint: n=%d err=%v", n, err)
+	}
+
+	events, err := store.Events(ctx, eth.EventFilter{Name: "SubscriberEmailAdded"})
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	if events[0].Fields["email"] != "second@example.com" || events[0].Fields["account"] != from.Hex() {
+		t.Fatalf("unexpected newest event: %+v", events[0])
+	}
+	if events[1].Contract != types.SUBSCRIBERSTORAGE || events[1].BlockNumber >= events[0].BlockNumber {
+		t.Fatalf("unexpected order: %+v", events)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	firebaseAdmin "firebase.google.com/go/v4"
@@ -73,15 +74,43 @@ func Read[T any](ctx context.Context, c *Client, path string) (T, bool, error) {
 		return zero, false, nil
 	}
 
+	if err := decode(raw, &zero); err != nil {
+		return zero, false, err
+	}
+
+	return zero, true, nil
+}
+
+// decode converts raw database data into v. The database returns objects
+// whose keys are mostly sequential integers, such as token IDs, as arrays, so
+// an array that does not fit v is retried as an index-keyed object.
+func decode(raw any, v any) error {
 	buf, err := json.Marshal(raw)
 	if err != nil {
-		return zero, false, fmt.Errorf("marshal firebase data: %w", err)
+		return fmt.Errorf("marshal firebase data: %w", err)
 	}
-	if err := json.Unmarshal(buf, &zero); err != nil {
-		return zero, false, fmt.Errorf("decode firebase data: %w", err)
+	err = json.Unmarshal(buf, v)
+	if err == nil {
+		return nil
 	}
 
-	return zero, true, nil
+	items, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("decode firebase data: %w", err)
+	}
+	obj := make(map[string]any, len(items))
+	for i, item := range items {
+		if item != nil {
+			obj[strconv.Itoa(i)] = item
+		}
+	}
+	if buf, err = json.Marshal(obj); err != nil {
+		return fmt.Errorf("marshal firebase data: %w", err)
+	}
+	if err := json.Unmarshal(buf, v); err != nil {
+		return fmt.Errorf("decode firebase data: %w", err)
+	}
+	return nil
 }
 
 func Write(ctx context.Context, c *Client, path string, value any) error {
@@ -97,3 +126,50 @@ func Write(ctx context.Context, c *Client, path string, value any) error {
 	ref := c.db.NewRef(norm)
 	return ref.Set(ctx, value)
 }
+
+// Transaction atomically replaces the value at path with fn's result,
+// re-running fn when another client writes first. fn receives the current
+// value and whether it exists; an error from fn aborts without writing.
+func Transaction[T any](ctx context.Context, c *Client, path string, fn func(cur T, exists bool) (T, error)) (T, error) {
+	var result T
+	if c == nil || c.db == nil {
+		return result, fmt.Errorf("firebase client is nil")
+	}
+
+	norm, err := normalizePath(path)
+	if err != nil {
+		return result, err
+	}
+
+	err = c.db.NewRef(norm).Transaction(ctx, func(node db.TransactionNode) (any, error) {
+		var (
+			cur    T
+			raw    any
+			exists bool
+		)
+		if err := node.Unmarshal(&raw); err != nil {
+			return nil, fmt.Errorf("decode firebase data: %w", err)
+		}
+		if raw != nil {
+			if err := decode(raw, &cur); err != nil {
+				return nil, err
+			}
+			exists = true
+		}
+		next, err := fn(cur, exists)
+		if err != nil {
+			return nil, err
+		}
+		result = next
+		return next, nil
+	})
+	return result, err
+}
+
+// Increment atomically adds delta to the integer at path and returns the new
+// value. A missing value counts as zero.
+func Increment(ctx context.Context, c *Client, path string, delta int64) (int64, error) {
+	return Transaction(ctx, c, path, func(cur int64, _ bool) (int64, error) {
+		return cur + delta, nil
+	})
+}
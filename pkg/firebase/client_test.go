@@ -0,0 +1,18 @@
+package firebase
+
+import "testing"
+
+func TestDecodeIntegerKeyedArray(t *testing.T) {
+	var got map[string]int64
+	if err := decode([]any{nil, float64(5), nil, float64(2)}, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 2 || got["1"] != 5 || got["3"] != 2 {
+		t.Fatalf("decode = %v", got)
+	}
+
+	var list []string
+	if err := decode([]any{"a", "b"}, &list); err != nil || len(list) != 2 {
+		t.Fatalf("decode slice = %v, %v", list, err)
+	}
+}
@@ -0,0 +1,157 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"in-server/pkg/apperr"
+	"in-server/pkg/config"
+)
+
+const (
+	DefaultEndpoint = "https://generativelanguage.googleapis.com/v1beta"
+	DefaultModel    = "gemini-1.5-flash"
+)
+
+type Client struct {
+	apiKey     string
+	endpoint   string
+	model      string
+	httpClient *http.Client
+}
+
+// New builds a client from the Google config block. A missing API key is
+// only reported when the client is used, so the server still starts.
+func New(cfg config.Config) *Client {
+	endpoint := strings.TrimRight(strings.TrimSpace(cfg.Google.GeminiEndpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	model := strings.TrimSpace(cfg.Google.GeminiModel)
+	if model == "" {
+		model = DefaultModel
+	}
+	return &Client{
+		apiKey:     strings.TrimSpace(cfg.Google.GeminiAPIKey),
+		endpoint:   endpoint,
+		model:      model,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type part struct {
+	Text string `json:"text"`
+}
+
+type content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []part `json:"parts"`
+}
+
+type generateRequest struct {
+	Contents         []content        `json:"contents"`
+	GenerationConfig generationConfig `json:"generationConfig"`
+}
+
+type generationConfig struct {
+	ResponseMimeType string  `json:"responseMimeType,omitempty"`
+	Temperature      float64 `json:"temperature"`
+}
+
+type generateResponse struct {
+	Candidates []struct {
+		Content content `json:"content"`
+	} `json:"candidates"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// GenerateText sends a single-turn prompt and returns the first candidate's
+// text.
+func (c *Client) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return c.generate(ctx, prompt, "")
+}
+
+// GenerateJSON asks for a JSON response and decodes it into out.
+func (c *Client) GenerateJSON(ctx context.Context, prompt string, out any) error {
+	text, err := c.generate(ctx, prompt, "application/json")
+	if err != nil {
+		return err
+	}
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text)), out); err != nil {
+		return apperr.Wrap(err, apperr.Gemini.ErrInvalidResponse.Code, apperr.Gemini.ErrInvalidResponse.Message, apperr.Gemini.ErrInvalidResponse.Status)
+	}
+	return nil
+}
+
+func (c *Client) generate(ctx context.Context, prompt, mimeType string) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if c == nil || c.apiKey == "" {
+		return "", apperr.Gemini.ErrAPIKeyMissing
+	}
+
+	body, err := json.Marshal(generateRequest{
+		Contents:         []content{{Role: "user", Parts: []part{{Text: prompt}}}},
+		GenerationConfig: generationConfig{ResponseMimeType: mimeType, Temperature: 0.4},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal gemini request: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/models/%s:generateContent", c.endpoint, url.PathEscape(c.model))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("build gemini request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", apperr.Wrap(err, apperr.Gemini.ErrRequestFailed.Code, apperr.Gemini.ErrRequestFailed.Message, apperr.Gemini.ErrRequestFailed.Status)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", apperr.Wrap(err, apperr.Gemini.ErrRequestFailed.Code, apperr.Gemini.ErrRequestFailed.Message, apperr.Gemini.ErrRequestFailed.Status)
+	}
+
+	var out generateResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return "", apperr.Wrap(fmt.Errorf("status %d: %w", resp.StatusCode, err), apperr.Gemini.ErrInvalidResponse.Code, apperr.Gemini.ErrInvalidResponse.Message, apperr.Gemini.ErrInvalidResponse.Status)
+	}
+	if resp.StatusCode != http.StatusOK || out.Error != nil {
+		msg := fmt.Sprintf("status %d", resp.StatusCode)
+		if out.Error != nil {
+			msg = fmt.Sprintf("%s: %s", msg, out.Error.Message)
+		}
+		return "", apperr.Wrap(fmt.Errorf("%s", msg), apperr.Gemini.ErrRequestFailed.Code, apperr.Gemini.ErrRequestFailed.Message, apperr.Gemini.ErrRequestFailed.Status)
+	}
+
+	var b strings.Builder
+	if len(out.Candidates) > 0 {
+		for _, p := range out.Candidates[0].Content.Parts {
+			b.WriteString(p.Text)
+		}
+	}
+	if b.Len() == 0 {
+		return "", apperr.Gemini.ErrInvalidResponse
+	}
+	return b.String(), nil
+}
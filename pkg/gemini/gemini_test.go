@@ -0,0 +1,47 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"in-server/pkg/config"
+)
+
+func TestGenerateJSONAgainstFakeServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/test-model:generateContent" || r.Header.Get("x-goog-api-key") != "key" {
+			http.Error(w, `{"error":{"code":401,"message":"bad request"}}`, http.StatusUnauthorized)
+			return
+		}
+		var req generateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.GenerationConfig.ResponseMimeType != "application/json" {
+			http.Error(w, `{"error":{"code":400,"message":"bad body"}}`, http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"` + "```json\\n" + `{\"summary\":\"ok\"}` + "\\n```" + `"}]}}]}`))
+	}))
+	defer srv.Close()
+
+	var cfg config.Config
+	cfg.Google.GeminiAPIKey = "key"
+	cfg.Google.GeminiEndpoint = srv.URL
+	cfg.Google.GeminiModel = "test-model"
+
+	var out struct {
+		Summary string `json:"summary"`
+	}
+	if err := New(cfg).GenerateJSON(context.Background(), "hi", &out); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if out.Summary != "ok" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+
+	cfg.Google.GeminiAPIKey = "wrong"
+	if err := New(cfg).GenerateJSON(context.Background(), "hi", &out); err == nil {
+		t.Fatalf("expected error from fake server")
+	}
+}
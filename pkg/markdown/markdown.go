@@ -0,0 +1,215 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+type Result struct {
+	HTML string    `json:"html"`
+	TOC  []Heading `json:"toc"`
+}
+
+var (
+	md = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(bluemonday.SpaceSeparatedTokens).OnElements("code")
+	p.AllowAttrs("checked", "disabled", "type").OnElements("input")
+	return p
+}
+
+// Render converts Markdown to allowlist-sanitized HTML. Headings get stable
+// anchor IDs that also work for Korean text, and are collected into a TOC.
+func Render(src string) (Result, error) {
+	source := []byte(src)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := md.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+
+	toc := []Heading{}
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		h, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		id, _ := h.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		toc = append(toc, Heading{
+			Level: h.Level,
+			Text:  strings.TrimSpace(string(headingText(h, source))),
+			ID:    string(idBytes),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("walk markdown: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, source, doc); err != nil {
+		return Result{}, fmt.Errorf("render markdown: %w", err)
+	}
+
+	return Result{
+		HTML: policy.Sanitize(buf.String()),
+		TOC:  toc,
+	}, nil
+}
+
+func headingText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			buf.Write(t.Segment.Value(source))
+			continue
+		}
+		buf.Write(headingText(c, source))
+	}
+	return buf.Bytes()
+}
+
+// headingIDs generates slug IDs that keep any Unicode letter or digit and
+// de-duplicates repeats with a numeric suffix.
+type headingIDs struct {
+	seen map[string]int
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{seen: make(map[string]int)}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case (unicode.IsSpace(r) || r == '-' || r == '_') && b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	id := strings.TrimRight(b.String(), "-")
+	if id == "" {
+		id = "section"
+	}
+
+	if n, ok := h.seen[id]; ok {
+		h.seen[id] = n + 1
+		id = id + "-" + strconv.Itoa(n+1)
+	} else {
+		h.seen[id] = 0
+	}
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.seen[string(value)] = 0
+}
+
+// PlainText strips Markdown syntax and returns the text of every block,
+// one block per line. Code blocks are kept since they still take time to read.
+func PlainText(src string) string {
+	return strings.Join(blocks(src, false), "\n")
+}
+
+// FirstParagraph returns the plain text of the first prose paragraph, skipping
+// headings, code and other non-paragraph blocks.
+func FirstParagraph(src string) string {
+	paras := blocks(src, true)
+	if len(paras) == 0 {
+		return ""
+	}
+	return paras[0]
+}
+
+func blocks(src string, paragraphsOnly bool) []string {
+	source := []byte(src)
+	doc := md.Parser().Parse(text.NewReader(source))
+
+	var out []string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Paragraph, *ast.TextBlock:
+			if _, inList := n.Parent().(*ast.ListItem); paragraphsOnly && inList {
+				return ast.WalkSkipChildren, nil
+			}
+		case *ast.Heading:
+			if paragraphsOnly {
+				return ast.WalkSkipChildren, nil
+			}
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			if !paragraphsOnly {
+				var buf bytes.Buffer
+				lines := n.Lines()
+				for i := 0; i < lines.Len(); i++ {
+					seg := lines.At(i)
+					buf.Write(seg.Value(source))
+				}
+				if s := strings.TrimSpace(buf.String()); s != "" {
+					out = append(out, s)
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		if s := strings.TrimSpace(inlineText(n, source)); s != "" {
+			out = append(out, s)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return out
+}
+
+func inlineText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			b.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(c.Value)
+		case *ast.AutoLink:
+			b.Write(c.Label(source))
+		default:
+			b.WriteString(inlineText(c, source))
+		}
+	}
+	return b.String()
+}
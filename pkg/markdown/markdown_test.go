@@ -0,0 +1,46 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitizesAndBuildsTOC(t *testing.T) {
+	src := strings.Join([]string{
+		"# 소개 Intro",
+		"",
+		"Hello <script>alert(1)</script> **world**",
+		"",
+		"## Setup",
+		"",
+		"[link](javascript:alert(1))",
+		"",
+		"## Setup",
+	}, "\n")
+
+	out, err := Render(src)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	if strings.Contains(out.HTML, "<script") || strings.Contains(out.HTML, "javascript:") {
+		t.Fatalf("html not sanitized:\n%s", out.HTML)
+	}
+	if !strings.Contains(out.HTML, `<h1 id="소개-intro">`) {
+		t.Fatalf("missing heading anchor:\n%s", out.HTML)
+	}
+
+	want := []Heading{
+		{Level: 1, Text: "소개 Intro", ID: "소개-intro"},
+		{Level: 2, Text: "Setup", ID: "setup"},
+		{Level: 2, Text: "Setup", ID: "setup-1"},
+	}
+	if len(out.TOC) != len(want) {
+		t.Fatalf("unexpected toc: %+v", out.TOC)
+	}
+	for i := range want {
+		if out.TOC[i] != want[i] {
+			t.Fatalf("toc %d: got %+v want %+v", i, out.TOC[i], want[i])
+		}
+	}
+}